@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// subcommandArgs returns the raw arguments following an agent subcommand, flags included
+func subcommandArgs(args []string, subCommand string) []string {
+	for i, arg := range args {
+		if arg == subCommand {
+			return args[i+1:]
+		}
+	}
+	return nil
+}
+
+func handleAgentAdd(registry *agent.Registry, args []string) {
+	fs := newCommandFlags("agent add", printAgentAddUsage)
+	idFlag := fs.String("id", "", "Agent ID to use instead of the file name")
+	forceFlag := fs.Bool("force", false, "Replace an existing agent with the same ID")
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) != 1 {
+		ui.Error("Expected exactly one agent file or URL")
+		printAgentAddUsage()
+		os.Exit(ExitUsageError)
+	}
+	source := positional[0]
+
+	content, name, err := readAgentSource(source)
+	if err != nil {
+		handleCommandError("Agent add", err, ExitAgentError)
+	}
+
+	id := *idFlag
+	if id == "" {
+		id = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	store := agent.NewFileRegistry(registry)
+	if registry.AgentExists(id) && !*forceFlag {
+		handleCommandError("Agent add", fmt.Errorf("agent %s already exists; use --force to replace it", id), ExitAgentError)
+	}
+
+	config := &agent.AgentConfig{
+		Metadata: agent.AgentMetadata{ID: id},
+		Settings: map[string]interface{}{agent.SettingContent: string(content)},
+	}
+	// The existing agent is only replaced once the new one has been written and resolved
+	if err := store.ReplaceAgent(config); err != nil {
+		handleCommandError("Agent add", err, ExitAgentError)
+	}
+
+	added, err := store.GetAgent(id)
+	if err != nil {
+		handleCommandError("Agent add", err, ExitAgentError)
+	}
+
+	fmt.Println()
+	ui.Success("Added agent %s from %s", id, source)
+	if added.Metadata.Description != "" {
+		ui.Plain("  %s", added.Metadata.Description)
+	}
+	ui.Plain("Reference it in your editor using @%s.mdc", id)
+	fmt.Println()
+}
+
+func handleAgentRemove(manager *core.AgentInitializer, registry *agent.Registry, args []string) {
+	fs := newCommandFlags("agent remove", printAgentRemoveUsage)
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) != 1 {
+		ui.Error("Expected exactly one agent ID")
+		printAgentRemoveUsage()
+		os.Exit(ExitUsageError)
+	}
+	id := positional[0]
+
+	if err := agent.NewFileRegistry(registry).RemoveAgent(id); err != nil {
+		handleCommandError("Agent remove", err, ExitAgentError)
+	}
+
+	excluded, err := manager.ExcludeAgent(id)
+	if err != nil {
+		handleCommandError("Agent remove", err, ExitAgentError)
+	}
+
+	fmt.Println()
+	ui.Success("Removed agent %s", id)
+	if excluded {
+		ui.Info("The agent was installed by cursor++ and will not be installed again; use %s to bring it back",
+			ui.SuccessStyle.Sprint("cursor++ init --all"))
+	}
+	fmt.Println()
+}
+
+// readAgentSource reads an agent file from a local path or an http(s) URL.
+// It returns the content and the file name the agent ID defaults to.
+func readAgentSource(source string) ([]byte, string, error) {
+	maxSize := core.DefaultParserConfig().MaxFileSize
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		parsed, err := url.Parse(source)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid URL %s: %v", source, err)
+		}
+
+		client := &http.Client{Timeout: core.DefaultParserConfig().HTTPTimeout}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to download %s: %v", source, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to download %s: %s", source, resp.Status)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to download %s: %v", source, err)
+		}
+		if int64(len(content)) > maxSize {
+			return nil, "", fmt.Errorf("%s is larger than %d bytes", source, maxSize)
+		}
+		return content, path.Base(parsed.Path), nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read agent file: %v", err)
+	}
+	if info.IsDir() {
+		return nil, "", fmt.Errorf("%s is a directory, expected an agent file", source)
+	}
+	if info.Size() > maxSize {
+		return nil, "", fmt.Errorf("%s is larger than %d bytes", source, maxSize)
+	}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read agent file: %v", err)
+	}
+
+	utils.Debug("Read agent file | path=" + source)
+	return content, filepath.Base(source), nil
+}
+
+func printAgentAddUsage() {
+	ui.Header("Usage: cursor++ agent add <file|url> [OPTIONS]")
+
+	ui.Plain("\nCopies an agent definition into the project's rules directory.")
+	ui.Plain("The agent ID is the file name without its extension.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --id <id>    Use this agent ID instead of the file name")
+	ui.Plain("  --force      Replace an existing agent with the same ID")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent add ./team-reviewer.mdc")
+	ui.Plain("  cursor++ agent add https://example.com/agents/reviewer.mdc --id team-reviewer")
+}
+
+func printAgentRemoveUsage() {
+	ui.Header("Usage: cursor++ agent remove <id>")
+
+	ui.Plain("\nDeletes an agent from the project's rules directory. Agents installed by")
+	ui.Plain("cursor++ are also excluded from later init and update runs.")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent remove team-reviewer")
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// Output formats of agent graph
+const (
+	graphFormatMermaid = "mermaid"
+	graphFormatDOT     = "dot"
+	graphFormatJSON    = "json"
+)
+
+// agentGraphFlags holds the flags of agent graph
+type agentGraphFlags struct {
+	format *string
+	output *string
+}
+
+// newAgentGraphFlags defines the flags of agent graph
+func newAgentGraphFlags() (*flag.FlagSet, *agentGraphFlags) {
+	fs := newCommandFlags("agent graph", printAgentGraphUsage)
+	return fs, &agentGraphFlags{
+		format: fs.String("format", graphFormatMermaid, "Output format: mermaid, dot or json"),
+		output: fs.String("output", "", "Write the graph to a file instead of standard output"),
+	}
+}
+
+// machineReadable reports whether the graph is printed to standard output
+func (f *agentGraphFlags) machineReadable() bool {
+	return *f.output == ""
+}
+
+func handleAgentGraph(config *utils.Config, rulesDir string, args []string) {
+	fs, options := newAgentGraphFlags()
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) > 1 {
+		ui.Error("Expected at most one rules directory")
+		printAgentGraphUsage()
+		os.Exit(ExitUsageError)
+	}
+	if len(positional) == 1 {
+		rulesDir = positional[0]
+	}
+
+	switch *options.format {
+	case graphFormatMermaid, graphFormatDOT, graphFormatJSON:
+	default:
+		ui.Error("Unknown format %q; use mermaid, dot or json", *options.format)
+		os.Exit(ExitUsageError)
+	}
+
+	if !utils.DirExists(rulesDir) {
+		handleCommandError("Agent graph", fmt.Errorf("rules directory not found: %s", rulesDir), ExitAgentError)
+	}
+	registry, err := agent.NewRegistry(config, rulesDir)
+	if err != nil {
+		handleCommandError("Agent graph", err, ExitAgentError)
+	}
+
+	graph, err := core.BuildAgentGraph(registry)
+	if err != nil {
+		handleCommandError("Agent graph", err, ExitAgentError)
+	}
+
+	var out io.Writer = os.Stdout
+	if *options.output != "" {
+		file, err := os.Create(*options.output)
+		if err != nil {
+			handleCommandError("Agent graph", fmt.Errorf("cannot create graph file: %v", err), ExitAgentError)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch *options.format {
+	case graphFormatDOT:
+		err = graph.WriteDOT(out)
+	case graphFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(graph)
+	default:
+		err = graph.WriteMermaid(out)
+	}
+	if err != nil {
+		handleCommandError("Agent graph", fmt.Errorf("cannot write graph: %v", err), ExitAgentError)
+	}
+
+	// Problems are part of the graph itself on standard output; report them when writing a file
+	if *options.output == "" {
+		return
+	}
+	fmt.Println()
+	ui.Success("Wrote graph of %d agents and %d handoffs to %s", len(graph.Nodes), len(graph.Edges), *options.output)
+	for _, edge := range graph.Dangling {
+		ui.Warning("%s refers to @%s, which is not installed (line %d)", edge.From, edge.To, edge.Line)
+	}
+	if len(graph.Orphans) > 0 {
+		ui.Info("No agent hands off to: %s", strings.Join(graph.Orphans, ", "))
+	}
+	fmt.Println()
+}
+
+func printAgentGraphUsage() {
+	ui.Header("Usage: cursor++ agent graph [dir] [OPTIONS]")
+
+	ui.Plain("\nDraws which agents hand off to which, from the @agent and @agent.mdc references")
+	ui.Plain("in their content. References to agents that are not installed are drawn dashed in")
+	ui.Plain("red, and agents no other agent hands off to are outlined in orange. The directory")
+	ui.Plain("defaults to .cursor/rules.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --format <f>     Output format: mermaid (default), dot or json")
+	ui.Plain("  --output <file>  Write the graph to a file instead of standard output")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent graph")
+	ui.Plain("  cursor++ agent graph --format dot | dot -Tsvg > agents.svg")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+	"cursor++/internal/version"
+)
+
+// Output formats of agent lint
+const (
+	lintFormatText  = "text"
+	lintFormatJSON  = "json"
+	lintFormatSARIF = "sarif"
+)
+
+// agentLintFlags holds the flags of agent lint
+type agentLintFlags struct {
+	format *string
+	output *string
+	strict *bool
+}
+
+// newAgentLintFlags defines the flags of agent lint
+func newAgentLintFlags() (*flag.FlagSet, *agentLintFlags) {
+	fs := newCommandFlags("agent lint", printAgentLintUsage)
+	return fs, &agentLintFlags{
+		format: fs.String("format", lintFormatText, "Output format: text, json or sarif"),
+		output: fs.String("output", "", "Write the report to a file instead of standard output"),
+		strict: fs.Bool("strict", false, "Also fail on warnings"),
+	}
+}
+
+// machineReadable reports whether the report is printed as JSON or SARIF to standard output
+func (f *agentLintFlags) machineReadable() bool {
+	return *f.format != lintFormatText && *f.output == ""
+}
+
+func handleAgentLint(rulesDir string, args []string) {
+	fs, options := newAgentLintFlags()
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) > 1 {
+		ui.Error("Expected at most one rules directory")
+		printAgentLintUsage()
+		os.Exit(ExitUsageError)
+	}
+	if len(positional) == 1 {
+		rulesDir = positional[0]
+	}
+
+	switch *options.format {
+	case lintFormatText, lintFormatJSON, lintFormatSARIF:
+	default:
+		ui.Error("Unknown format %q; use text, json or sarif", *options.format)
+		os.Exit(ExitUsageError)
+	}
+
+	report, err := core.LintRules(rulesDir, core.DefaultParserConfig())
+	if err != nil {
+		handleCommandError("Agent lint", err, ExitLintError)
+	}
+
+	var out io.Writer = os.Stdout
+	if *options.output != "" {
+		file, err := os.Create(*options.output)
+		if err != nil {
+			handleCommandError("Agent lint", fmt.Errorf("cannot create report file: %v", err), ExitLintError)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch *options.format {
+	case lintFormatJSON:
+		err = report.WriteJSON(out)
+	case lintFormatSARIF:
+		err = report.WriteSARIF(out, version.GetVersion(), sarifURIPrefix(rulesDir))
+	default:
+		printLintReport(out, report)
+	}
+	if err != nil {
+		handleCommandError("Agent lint", fmt.Errorf("cannot write report: %v", err), ExitLintError)
+	}
+
+	utils.Infof("Agent lint completed | files=%d errors=%d warnings=%d", report.Files, report.Errors, report.Warnings)
+	if report.Errors > 0 || (*options.strict && report.Warnings > 0) {
+		if file, ok := out.(*os.File); ok && file != os.Stdout {
+			file.Close()
+		}
+		os.Exit(ExitLintError)
+	}
+}
+
+// printLintReport writes diagnostics in a compiler-like format, one per line
+func printLintReport(w io.Writer, report *core.LintReport) {
+	fmt.Fprintln(w)
+	for _, d := range report.Diagnostics {
+		location := filepath.Join(report.Dir, d.File)
+		if d.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, d.Line)
+		}
+
+		severity := ui.WarnStyle.Sprint(d.Severity)
+		if d.Severity == core.SeverityError {
+			severity = ui.ErrorStyle.Sprint(d.Severity)
+		}
+		fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, severity, d.Message, d.Rule)
+	}
+	if len(report.Diagnostics) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	summary := fmt.Sprintf("%d file(s) checked, %d error(s), %d warning(s)", report.Files, report.Errors, report.Warnings)
+	switch {
+	case report.Errors > 0:
+		fmt.Fprintln(w, ui.ErrorStyle.Sprint("✗ "+summary))
+	case report.Warnings > 0:
+		fmt.Fprintln(w, ui.WarnStyle.Sprint("⚠ "+summary))
+	default:
+		fmt.Fprintln(w, ui.SuccessStyle.Sprint("✓ "+summary))
+	}
+}
+
+// sarifURIPrefix returns the rules directory relative to the working directory, as code
+// scanning tools resolve SARIF locations against the repository root
+func sarifURIPrefix(rulesDir string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(rulesDir)
+	}
+	absDir, err := filepath.Abs(rulesDir)
+	if err != nil {
+		return filepath.ToSlash(rulesDir)
+	}
+	rel, err := filepath.Rel(cwd, absDir)
+	if err != nil {
+		return filepath.ToSlash(absDir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func printAgentLintUsage() {
+	ui.Header("Usage: cursor++ agent lint [dir] [OPTIONS]")
+
+	ui.Plain("\nChecks agent definitions for problems: frontmatter syntax, invalid globs,")
+	ui.Plain("missing title or Role sections, invalid or duplicate IDs, oversized files and")
+	ui.Plain("broken @agent.mdc references. The directory defaults to .cursor/rules.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --format <f>     Output format: text (default), json or sarif")
+	ui.Plain("  --output <file>  Write the report to a file instead of standard output")
+	ui.Plain("  --strict         Also fail on warnings")
+
+	ui.Plain("\nExit codes:")
+	ui.Plain("  0   No errors (and no warnings with --strict)")
+	ui.Plain("  40  Errors found (or warnings with --strict)")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent lint")
+	ui.Plain("  cursor++ agent lint rules --format sarif --output lint.sarif")
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/ui"
+)
+
+// attachModes are the choices offered for when Cursor should include a new agent
+var attachModes = []string{
+	agent.AttachManual + " (referenced with @)",
+	agent.AttachAgentRequested + " (Cursor decides from the description)",
+	agent.AttachAuto + " (when matching files are in context)",
+	agent.AttachAlways + " (every request)",
+}
+
+func handleAgentNew(registry *agent.Registry, args []string) {
+	fs := newCommandFlags("agent new", printAgentNewUsage)
+	nameFlag := fs.String("name", "", "Display name")
+	iconFlag := fs.String("icon", "", "Icon shown before the name")
+	descriptionFlag := fs.String("description", "", "One sentence summary")
+	roleFlag := fs.String("role", "", "Paragraph describing the agent's role")
+	categoryFlag := fs.String("category", "", "Category to group the agent under")
+	tagsFlag := fs.String("tags", "", "Comma separated tags")
+	globsFlag := fs.String("globs", "", "Comma separated globs the agent auto-attaches to")
+	alwaysFlag := fs.Bool("always-apply", false, "Include the agent in every request")
+	handoffFlag := fs.String("handoff", "", "Comma separated IDs of agents to recommend next")
+	authorFlag := fs.String("author", "", "Author")
+	versionFlag := fs.String("version", "1.0", "Version")
+	noInputFlag := fs.Bool("no-input", false, "Do not prompt; use flags and placeholders only")
+	var reminders, responsibilities stringList
+	fs.Var(&reminders, "reminder", "Important reminder (repeatable)")
+	fs.Var(&responsibilities, "responsibility", "Core responsibility heading (repeatable)")
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) > 1 {
+		ui.Error("Expected at most one agent ID")
+		printAgentNewUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	interactive := !*noInputFlag
+	validID := func(id string) bool {
+		if !agent.IsValidAgentID(id) {
+			ui.Warning("Agent IDs may not be empty or contain dots, slashes or spaces")
+			return false
+		}
+		if registry.AgentExists(id) {
+			ui.Warning("Agent %s already exists", id)
+			return false
+		}
+		return true
+	}
+	required := func(value string) bool { return strings.TrimSpace(value) != "" }
+
+	scaffold := &agent.Scaffold{
+		Name:             *nameFlag,
+		Icon:             *iconFlag,
+		Description:      *descriptionFlag,
+		Role:             *roleFlag,
+		Category:         *categoryFlag,
+		Tags:             splitList(*tagsFlag),
+		Globs:            agent.SplitList(*globsFlag),
+		AlwaysApply:      *alwaysFlag,
+		Handoffs:         splitList(*handoffFlag),
+		Author:           *authorFlag,
+		Version:          *versionFlag,
+		Reminders:        reminders,
+		Responsibilities: responsibilities,
+	}
+
+	if len(positional) == 1 {
+		scaffold.ID = positional[0]
+	} else if interactive {
+		scaffold.ID = ui.PromptInput("Agent ID (e.g. release-manager):", validID)
+	}
+	if !interactive || len(positional) == 1 {
+		if !agent.IsValidAgentID(scaffold.ID) {
+			handleCommandError("Agent new", fmt.Errorf("invalid agent ID: %q", scaffold.ID), ExitUsageError)
+		}
+		if registry.AgentExists(scaffold.ID) {
+			handleCommandError("Agent new", fmt.Errorf("agent %s already exists", scaffold.ID), ExitAgentError)
+		}
+	}
+
+	if interactive {
+		promptScaffold(scaffold, required, *globsFlag == "" && !*alwaysFlag)
+	}
+
+	if scaffold.Name == "" {
+		scaffold.Name = agent.DefaultAgentName(scaffold.ID)
+	}
+	if scaffold.Description == "" && !interactive {
+		handleCommandError("Agent new", fmt.Errorf("--description is required with --no-input"), ExitUsageError)
+	}
+	if err := scaffold.Validate(); err != nil {
+		handleCommandError("Agent new", err, ExitUsageError)
+	}
+
+	for _, id := range scaffold.Handoffs {
+		if !registry.AgentExists(id) {
+			ui.Warning("Handoff agent %s is not installed in this project", id)
+		}
+	}
+
+	store := agent.NewFileRegistry(registry)
+	config := &agent.AgentConfig{
+		Metadata: agent.AgentMetadata{ID: scaffold.ID},
+		Settings: map[string]interface{}{agent.SettingContent: scaffold.Render()},
+	}
+	if err := store.AddAgent(config); err != nil {
+		handleCommandError("Agent new", err, ExitAgentError)
+	}
+
+	fmt.Println()
+	ui.Success("Created agent %s in %s", scaffold.ID, registry.GetRulesDir())
+	ui.Plain("Fill in the TODO placeholders, then reference it in your editor using @%s.mdc", scaffold.ID)
+	fmt.Println()
+}
+
+// promptScaffold asks for every scaffold field that was not given as a flag
+func promptScaffold(s *agent.Scaffold, required func(string) bool, askAttach bool) {
+	if s.Name == "" {
+		s.Name = ui.PromptInputWithDefault("Display name:", agent.DefaultAgentName(s.ID), required)
+	}
+	if s.Description == "" {
+		s.Description = ui.PromptInput("One sentence description:", required)
+	}
+	if s.Role == "" {
+		s.Role = ui.PromptInput("Role paragraph (empty for a placeholder):", nil)
+	}
+
+	if s.Category == "" {
+		choices := append(append([]string(nil), ui.AgentCategories...), "Other (enter a name)")
+		choice := ui.PromptOptions("Category:", choices)
+		if choice == len(ui.AgentCategories) {
+			s.Category = ui.PromptInput("Category name:", required)
+		} else {
+			s.Category = ui.AgentCategories[choice]
+		}
+	}
+
+	if askAttach {
+		switch ui.PromptOptions("When should Cursor include this agent?", attachModes) {
+		case 1:
+			// Agent Requested only needs the description
+		case 2:
+			s.Globs = agent.SplitList(ui.PromptInput("Comma separated globs (e.g. **/*.go):", required))
+		case 3:
+			s.AlwaysApply = true
+		}
+	}
+
+	if len(s.Reminders) == 0 {
+		s.Reminders = promptList("Important reminder")
+	}
+	if len(s.Responsibilities) == 0 {
+		s.Responsibilities = promptList("Core responsibility")
+	}
+	if len(s.Handoffs) == 0 {
+		s.Handoffs = splitList(ui.PromptInput("Agents to hand off to, comma separated (empty for none):", func(value string) bool {
+			for _, id := range splitList(value) {
+				if !agent.IsValidAgentID(id) {
+					return false
+				}
+			}
+			return true
+		}))
+	}
+}
+
+// promptList asks for list items one at a time until an empty answer
+func promptList(label string) []string {
+	var items []string
+	for {
+		item := ui.PromptInput(fmt.Sprintf("%s #%d (empty to finish):", label, len(items)+1), nil)
+		if item == "" {
+			return items
+		}
+		items = append(items, item)
+	}
+}
+
+func printAgentNewUsage() {
+	ui.Header("Usage: cursor++ agent new [id] [OPTIONS]")
+
+	ui.Plain("\nCreates a new agent definition with frontmatter and the standard section skeleton.")
+	ui.Plain("Anything not given as a flag is asked for interactively.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --name <name>            Display name (default: derived from the ID)")
+	ui.Plain("  --description <text>     One sentence summary")
+	ui.Plain("  --role <text>            Paragraph describing the agent's role")
+	ui.Plain("  --reminder <text>        Important reminder (repeatable)")
+	ui.Plain("  --responsibility <text>  Core responsibility heading (repeatable)")
+	ui.Plain("  --handoff <ids>          Comma separated IDs of agents to recommend next")
+	ui.Plain("  --category <name>        Category to group the agent under")
+	ui.Plain("  --tags <tags>            Comma separated tags")
+	ui.Plain("  --globs <globs>          Comma separated globs the agent auto-attaches to")
+	ui.Plain("  --always-apply           Include the agent in every request")
+	ui.Plain("  --icon <icon>            Icon shown before the name")
+	ui.Plain("  --author <name>          Author")
+	ui.Plain("  --version <version>      Version (default: 1.0)")
+	ui.Plain("  --no-input               Do not prompt; use flags and placeholders only")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent new                  # Answer prompts for everything")
+	ui.Plain("  cursor++ agent new release-manager --no-input \\")
+	ui.Plain("      --description \"Prepares releases and changelogs\" --handoff git-committer")
+}
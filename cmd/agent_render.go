@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// agentRenderFlags holds the flags of agent render
+type agentRenderFlags struct {
+	template *string
+	output   *string
+	force    *bool
+	noInput  *bool
+	vars     stringList
+}
+
+// newAgentRenderFlags defines the flags of agent render
+func newAgentRenderFlags() (*flag.FlagSet, *agentRenderFlags) {
+	fs := newCommandFlags("agent render", printAgentRenderUsage)
+	options := &agentRenderFlags{
+		template: fs.String("template", "", "Template to render (needed if the agent has several)"),
+		output:   fs.String("output", "", "Write the result to a file instead of standard output"),
+		force:    fs.Bool("force", false, "Overwrite the output file if it exists"),
+		noInput:  fs.Bool("no-input", false, "Fail instead of asking for missing variables"),
+	}
+	fs.Var(&options.vars, "var", "Template variable as key=value (repeatable)")
+	return fs, options
+}
+
+// machineReadable reports whether the result is printed to standard output
+func (f *agentRenderFlags) machineReadable() bool {
+	return *f.output == ""
+}
+
+func handleAgentRender(manager *core.AgentInitializer, registry *agent.Registry, args []string) {
+	fs, options := newAgentRenderFlags()
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) != 1 {
+		ui.Error("Expected exactly one agent ID")
+		printAgentRenderUsage()
+		os.Exit(ExitUsageError)
+	}
+	id := positional[0]
+
+	vars := make(map[string]string, len(options.vars))
+	for _, assignment := range options.vars {
+		key, value, found := strings.Cut(assignment, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			ui.Error("Invalid --var %q; expected key=value", assignment)
+			os.Exit(ExitUsageError)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+
+	definition, err := registry.GetAgent(id)
+	if err != nil {
+		handleCommandError("Agent render", err, ExitAgentError)
+	}
+	path, err := registry.TemplatePath(id, *options.template)
+	if err != nil {
+		handleCommandError("Agent render", err, ExitAgentError)
+	}
+	tmpl, err := core.LoadTemplate(path)
+	if err != nil {
+		handleCommandError("Agent render", err, ExitAgentError)
+	}
+
+	if *options.output != "" && utils.FileExists(*options.output) && !*options.force {
+		handleCommandError("Agent render", fmt.Errorf("%s already exists; use --force to overwrite it", *options.output), ExitAgentError)
+	}
+
+	// Prompts would end up in the output if it is redirected, so only ask on a terminal
+	if missing := tmpl.MissingVariables(vars); len(missing) > 0 {
+		if *options.noInput || !isTerminal(os.Stdin) || (*options.output == "" && !isTerminal(os.Stdout)) {
+			handleCommandError("Agent render", fmt.Errorf("missing template variables: %s; set them with --var key=value",
+				strings.Join(missing, ", ")), ExitUsageError)
+		}
+		required := func(value string) bool { return strings.TrimSpace(value) != "" }
+		for _, name := range missing {
+			vars[name] = ui.PromptInput(name+":", required)
+		}
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		handleCommandError("Agent render", err, ExitAgentError)
+	}
+
+	result, err := tmpl.Execute(path, core.TemplateData{
+		Agent:   definition,
+		Project: manager.ProjectFacts(currentDir),
+		Vars:    vars,
+	})
+	if err != nil {
+		handleCommandError("Agent render", err, ExitAgentError)
+	}
+
+	if *options.output == "" {
+		fmt.Print(result)
+		return
+	}
+
+	config := utils.NewConfigManager().GetConfig()
+	if err := os.WriteFile(*options.output, []byte(result), config.FilePermission); err != nil {
+		handleCommandError("Agent render", fmt.Errorf("cannot write %s: %v", *options.output, err), ExitAgentError)
+	}
+	ui.Success("Rendered %s to %s", path, *options.output)
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func printAgentRenderUsage() {
+	ui.Header("Usage: cursor++ agent render <id> [OPTIONS]")
+
+	ui.Plain("\nRenders one of an agent's templates (templates/<id>-<name>.tmpl in the rules")
+	ui.Plain("directory) with Go templating. Templates can use .Agent (the agent's metadata),")
+	ui.Plain(".Project (Name, Dir, Branch, Languages, Date) and .Vars (the --var values).")
+	ui.Plain("Missing variables are asked for when running in a terminal.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --template <name>  Template to render, needed if the agent has several")
+	ui.Plain("  --var <key=value>  Template variable (repeatable)")
+	ui.Plain("  --output <file>    Write the result to a file instead of standard output")
+	ui.Plain("  --force            Overwrite the output file if it exists")
+	ui.Plain("  --no-input         Fail instead of asking for missing variables")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent render git-committer --template pr --var ticket=ABC-123")
+	ui.Plain("  cursor++ agent render implementer --output PLAN.md")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+)
+
+// searchDescriptionWidth is the number of characters of an agent's description shown in results
+const searchDescriptionWidth = 100
+
+// agentSearchFlags holds the flags of agent search
+type agentSearchFlags struct {
+	limit    *int
+	snippets *int
+	json     *bool
+}
+
+// newAgentSearchFlags defines the flags of agent search
+func newAgentSearchFlags() (*flag.FlagSet, *agentSearchFlags) {
+	fs := newCommandFlags("agent search", printAgentSearchUsage)
+	return fs, &agentSearchFlags{
+		limit:    fs.Int("limit", 10, "Maximum number of agents to show"),
+		snippets: fs.Int("snippets", 3, "Matching lines to show per agent"),
+		json:     fs.Bool("json", false, "Print results as JSON"),
+	}
+}
+
+// machineReadable reports whether the results are printed as JSON
+func (f *agentSearchFlags) machineReadable() bool {
+	return *f.json
+}
+
+func handleAgentSearch(registry *agent.Registry, args []string) {
+	fs, options := newAgentSearchFlags()
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) == 0 {
+		ui.Error("Missing search query")
+		printAgentSearchUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	query, err := core.ParseSearchQuery(searchQueryFromArgs(positional))
+	if err != nil {
+		handleCommandError("Agent search", err, ExitUsageError)
+	}
+
+	results, err := core.SearchAgents(registry, query, *options.snippets)
+	if err != nil {
+		handleCommandError("Agent search", err, ExitAgentError)
+	}
+	total := len(results)
+	if *options.limit > 0 && len(results) > *options.limit {
+		results = results[:*options.limit]
+	}
+
+	if *options.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			handleCommandError("Agent search", err, ExitAgentError)
+		}
+		return
+	}
+
+	if total == 0 {
+		ui.Info("No agents match %s", searchQueryFromArgs(positional))
+		return
+	}
+
+	for _, result := range results {
+		fmt.Printf("\n%s %s", ui.HeaderStyle.Sprint(result.Name), ui.InfoStyle.Sprintf("(%s)", result.ID))
+		if len(query.Terms) > 0 {
+			fmt.Printf("  score %.2f", result.Score)
+		}
+		fmt.Println()
+		if description := []rune(result.Agent.Description); len(description) > searchDescriptionWidth {
+			fmt.Printf("  %s...\n", string(description[:searchDescriptionWidth-3]))
+		} else if len(description) > 0 {
+			fmt.Printf("  %s\n", string(description))
+		}
+		for _, match := range result.Matches {
+			fmt.Printf("  %s %s\n", ui.InfoStyle.Sprintf("%4d:", match.Line), highlightSpans(match.Text, match.Spans))
+		}
+	}
+
+	fmt.Println()
+	if total > len(results) {
+		ui.Info("Showing %d of %d matching agents; use --limit to see more", len(results), total)
+	} else {
+		ui.Info("%d matching agent(s)", total)
+	}
+}
+
+// searchQueryFromArgs joins the query arguments. The shell has already removed the quotes
+// around an argument with spaces, so such an argument is quoted again to keep it a phrase.
+func searchQueryFromArgs(args []string) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t") && !strings.Contains(arg, `"`) {
+			if field, value, found := strings.Cut(arg, ":"); found && !strings.ContainsAny(field, " \t") {
+				arg = field + `:"` + value + `"`
+			} else {
+				arg = `"` + arg + `"`
+			}
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// highlightSpans colors the given byte ranges of text
+func highlightSpans(text string, spans [][2]int) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		b.WriteString(text[last:span[0]])
+		b.WriteString(ui.MatchStyle.Sprint(text[span[0]:span[1]]))
+		last = span[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func printAgentSearchUsage() {
+	ui.Header("Usage: cursor++ agent search <query> [OPTIONS]")
+
+	ui.Plain("\nSearches the names, descriptions, tags and full content of the installed agents.")
+	ui.Plain("Every word must occur in an agent for it to match; results are ranked by where")
+	ui.Plain("and how often the words occur, with matches in names weighted highest.")
+
+	ui.Plain("\nQuery syntax:")
+	ui.Plain("  word              Match the word anywhere, ignoring case")
+	ui.Plain("  \"exact phrase\"    Match the words next to each other")
+	ui.Plain("  name:<text>       Only agents whose name or ID contains the text")
+	ui.Plain("  tag:<tag>         Only agents with the tag")
+	ui.Plain("  category:<text>   Only agents in a matching category")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --limit <n>     Maximum number of agents to show (default 10, 0 for all)")
+	ui.Plain("  --snippets <n>  Matching lines to show per agent (default 3)")
+	ui.Plain("  --json          Print results as JSON")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent search migration")
+	ui.Plain("  cursor++ agent search \"pull request\" tag:git")
+	ui.Plain("  cursor++ agent search name:implementer database")
+}
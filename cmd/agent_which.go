@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// agentWhichFlags holds the flags of agent which
+type agentWhichFlags struct {
+	all    *bool
+	json   *bool
+	budget *int
+}
+
+// newAgentWhichFlags defines the flags of agent which
+func newAgentWhichFlags(budget int) (*flag.FlagSet, *agentWhichFlags) {
+	fs := newCommandFlags("agent which", printAgentWhichUsage)
+	return fs, &agentWhichFlags{
+		all:    fs.Bool("all", false, "Also list the rules that do not attach, with the reason"),
+		json:   fs.Bool("json", false, "Print results as JSON"),
+		budget: fs.Int("budget", budget, "Estimated tokens the attached rules may use; 0 disables the warning"),
+	}
+}
+
+// machineReadable reports whether the results are printed as JSON
+func (f *agentWhichFlags) machineReadable() bool {
+	return *f.json
+}
+
+func handleAgentWhich(config *utils.Config, registry *agent.Registry, args []string) {
+	fs, options := newAgentWhichFlags(config.TokenBudget)
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) == 0 {
+		ui.Error("Missing file path")
+		printAgentWhichUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		handleCommandError("Agent which", err, ExitAgentError)
+	}
+
+	results := make([]core.FileRules, 0, len(positional))
+	for _, file := range positional {
+		rel, err := projectPath(projectDir, file)
+		if err != nil {
+			handleCommandError("Agent which", err, ExitUsageError)
+		}
+		results = append(results, core.WhichRules(registry, rel))
+	}
+
+	if *options.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			handleCommandError("Agent which", err, ExitAgentError)
+		}
+		return
+	}
+
+	for _, result := range results {
+		fmt.Println()
+		ui.Header("%s", result.Path)
+		if len(result.Attached()) == 0 {
+			fmt.Println("  " + ui.WarnStyle.Sprint("No rules attach to this file"))
+		}
+		for _, rule := range result.Rules {
+			if rule.Attached {
+				fmt.Printf("  %s %s %s  %s, %s\n", ui.SuccessStyle.Sprint("✓"), rule.ID,
+					ui.InfoStyle.Sprintf("(%s)", rule.Mode), rule.Reason, ui.FormatTokens(rule.Tokens))
+			} else if *options.all {
+				fmt.Printf("  %s %s %s  %s\n", "-", rule.ID, ui.InfoStyle.Sprintf("(%s)", rule.Mode), rule.Reason)
+			}
+		}
+
+		if len(result.Attached()) > 0 {
+			fmt.Printf("  Attached rules use %s together\n", ui.FormatTokens(result.Tokens))
+		}
+		if *options.budget > 0 && result.Tokens > *options.budget {
+			fmt.Println("  " + ui.WarnStyle.Sprintf("⚠ Over the token budget of %s", ui.FormatTokens(*options.budget)))
+		}
+	}
+	fmt.Println()
+}
+
+// projectPath turns a path given on the command line into a slash separated path relative
+// to the project directory, which is what rule globs are matched against
+func projectPath(projectDir, file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(projectDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the project directory %s", file, projectDir)
+	}
+	if rel == "." {
+		return "", fmt.Errorf("%s is the project directory; give a file path", file)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func printAgentWhichUsage() {
+	ui.Header("Usage: cursor++ agent which <path>... [OPTIONS]")
+
+	ui.Plain("\nShows which rules Cursor attaches when working on the given files, based on the")
+	ui.Plain("alwaysApply and globs frontmatter of the installed rules, and why, along with their")
+	ui.Plain("estimated token cost. Paths are taken relative to the project directory and do not")
+	ui.Plain("need to exist.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --all         Also list the rules that do not attach, with the reason")
+	ui.Plain("  --budget <n>  Warn when the attached rules are estimated to use more tokens")
+	ui.Plain("                (default: tokenBudget from the configuration, 0 to disable)")
+	ui.Plain("  --json        Print results as JSON")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ agent which internal/core/sync_test.go")
+	ui.Plain("  cursor++ agent which --all src/App.tsx src/App.test.tsx")
+}
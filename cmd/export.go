@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// exportFlags holds the flags of the export command
+type exportFlags struct {
+	format *string
+	output *string
+	force  *bool
+	dryRun *bool
+}
+
+// newExportFlags defines the flags of the export command
+func newExportFlags() (*flag.FlagSet, *exportFlags) {
+	fs := newCommandFlags("export", printExportUsage)
+	return fs, &exportFlags{
+		format: fs.String("format", "", "Assistant format to export to: "+strings.Join(core.ExportFormats, ", ")),
+		output: fs.String("output", "", "Write everything to this file, or - for standard output"),
+		force:  fs.Bool("force", false, "Overwrite files that were not written by cursor++ export"),
+		dryRun: fs.Bool("dry-run", false, "List the files that would be written without writing them"),
+	}
+}
+
+// machineReadable reports whether the export is printed to standard output, as plain Markdown is by default
+func (f *exportFlags) machineReadable() bool {
+	return *f.output == "-" || (*f.output == "" && *f.format == core.ExportMarkdown)
+}
+
+func handleExport(args []string) {
+	utils.Debug("Handling export command")
+
+	fs, options := newExportFlags()
+	parseCommandFlags(fs, args)
+
+	if *options.format == "" {
+		ui.Error("Missing --format")
+		printExportUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	configManager := utils.NewConfigManager()
+	if err := configManager.Load(); err != nil {
+		handleCommandError("Export", fmt.Errorf("cannot load configuration: %v", err), ExitAgentError)
+	}
+	config := configManager.GetConfig()
+
+	registry, projectDir := loadProjectRegistry("Export", "")
+	files, err := core.ExportAgents(registry, *options.format, *options.output != "")
+	if err != nil {
+		handleCommandError("Export", err, ExitUsageError)
+	}
+
+	// Plain Markdown has no conventional file, so it goes to standard output unless told otherwise
+	if *options.output == "-" || (*options.output == "" && files[0].Path == "") {
+		fmt.Print(files[0].Content)
+		return
+	}
+
+	targets := make([]string, len(files))
+	for i, file := range files {
+		targets[i] = filepath.Join(projectDir, filepath.FromSlash(file.Path))
+	}
+	if *options.output != "" {
+		targets[0] = *options.output
+	}
+
+	for _, target := range targets {
+		if !utils.FileExists(target) || *options.force {
+			continue
+		}
+		exported, err := core.IsExportedFile(target)
+		if err != nil {
+			handleCommandError("Export", err, ExitAgentError)
+		}
+		if !exported {
+			handleCommandError("Export", fmt.Errorf("%s exists and was not written by cursor++ export; use --force to overwrite it",
+				displayPath(projectDir, target)), ExitAgentError)
+		}
+	}
+
+	var stale []string
+	if *options.format == core.ExportCopilot && *options.output == "" {
+		if stale, err = core.StaleExportFiles(projectDir, files); err != nil {
+			handleCommandError("Export", err, ExitAgentError)
+		}
+	}
+
+	fmt.Println()
+	for i, file := range files {
+		path := displayPath(projectDir, targets[i])
+		if *options.dryRun {
+			ui.Plain("  write   %s %s", path, ui.InfoStyle.Sprintf("(%s, %s)", exportedAgents(file), ui.FormatTokens(agent.EstimateTokens(file.Content))))
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(targets[i]), config.DirPermission); err != nil {
+			handleCommandError("Export", fmt.Errorf("cannot create directory for %s: %v", path, err), ExitAgentError)
+		}
+		if err := os.WriteFile(targets[i], []byte(file.Content), config.FilePermission); err != nil {
+			handleCommandError("Export", fmt.Errorf("cannot write %s: %v", path, err), ExitAgentError)
+		}
+		ui.Plain("  wrote   %s %s", path, ui.InfoStyle.Sprintf("(%s)", exportedAgents(file)))
+	}
+	for _, path := range stale {
+		if *options.dryRun {
+			ui.Plain("  remove  %s", path)
+			continue
+		}
+		if err := os.Remove(filepath.Join(projectDir, filepath.FromSlash(path))); err != nil {
+			handleCommandError("Export", fmt.Errorf("cannot remove %s: %v", path, err), ExitAgentError)
+		}
+		ui.Plain("  removed %s", path)
+	}
+	fmt.Println()
+
+	// The main file is read on every request, so it is what counts against the budget
+	if tokens := agent.EstimateTokens(files[0].Content); config.TokenBudget > 0 && tokens > config.TokenBudget {
+		ui.Warning("%s is estimated at %s, over the token budget of %s; the assistant reads it on every request",
+			displayPath(projectDir, targets[0]), ui.FormatTokens(tokens), ui.FormatTokens(config.TokenBudget))
+	}
+
+	if *options.dryRun {
+		ui.Info("Dry run: nothing was written")
+		return
+	}
+	ui.Success("Exported %d agents to %s format", len(registry.ListAgents()), *options.format)
+}
+
+// exportedAgents describes the agents in an exported file, naming a single one
+func exportedAgents(file core.ExportFile) string {
+	if len(file.Agents) == 1 {
+		return file.Agents[0]
+	}
+	return fmt.Sprintf("%d agents", len(file.Agents))
+}
+
+func printExportUsage() {
+	ui.Header("Usage: cursor++ export --format <format> [OPTIONS]")
+
+	ui.Plain("\nConverts the project's installed agents into the instruction files of another")
+	ui.Plain("AI assistant, so the .mdc rules stay the single source of truth:")
+	ui.Plain("  copilot    .github/copilot-instructions.md, plus .github/instructions/<id>.instructions.md")
+	ui.Plain("             with an applyTo pattern for every rule with globs")
+	ui.Plain("  agents-md  AGENTS.md")
+	ui.Plain("  windsurf   .windsurfrules")
+	ui.Plain("  cline      .clinerules")
+	ui.Plain("  markdown   A single Markdown document, on standard output by default")
+	ui.Plain("Formats that cannot attach rules to files get every agent in one file, each")
+	ui.Plain("starting with a note on when it applies. Files written by an earlier export")
+	ui.Plain("are overwritten; other existing files need --force.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --format <format>  Format to export to (required)")
+	ui.Plain("  --output <file>    Write everything to this one file, or - for standard output")
+	ui.Plain("  --force            Overwrite files that were not written by cursor++ export")
+	ui.Plain("  --dry-run          List the files that would be written without writing them")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ export --format copilot")
+	ui.Plain("  cursor++ export --format agents-md --dry-run")
+	ui.Plain("  cursor++ export --format markdown > RULES.md")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"cursor++/internal/ui"
+)
+
+// newCommandFlags creates a flag set for a command that reports parse errors through our usage output
+func newCommandFlags(name string, usage func()) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = usage
+	return fs
+}
+
+// parseCommandFlags parses flags that may appear before, between or after positional arguments
+// and returns the positional arguments. On invalid input it prints usage and exits.
+func parseCommandFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				fs.Usage()
+				os.Exit(ExitSuccess)
+			}
+			ui.Error("%v", err)
+			fs.Usage()
+			os.Exit(ExitUsageError)
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// machineReadableWith returns a check of whether a command's arguments ask for output meant for
+// other tools. The arguments are parsed with the command's own flags, as newFlags defines them for
+// the command's handler, and invalid ones count as not machine-readable so their error is shown.
+func machineReadableWith[O interface{ machineReadable() bool }](newFlags func() (*flag.FlagSet, O)) func(args []string) bool {
+	return func(args []string) bool {
+		fs, options := newFlags()
+		fs.Usage = func() {}
+		for {
+			if err := fs.Parse(args); err != nil {
+				return false
+			}
+			if fs.NArg() == 0 {
+				return options.machineReadable()
+			}
+			args = fs.Args()[1:]
+		}
+	}
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stringList is a flag that may be given several times, collecting every value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+func handleImport(args []string) {
+	utils.Debug("Handling import command")
+
+	fs := newCommandFlags("import", printImportUsage)
+	formatFlag := fs.String("format", "", "Assistant format to import from: "+strings.Join(core.ImportFormats, ", "))
+	alwaysFlag := fs.Bool("always-apply", false, "Include the imported agents without globs in every request")
+	forceFlag := fs.Bool("force", false, "Replace installed agents with the same IDs")
+	dryRunFlag := fs.Bool("dry-run", false, "List the agents that would be created without writing them")
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) > 1 {
+		ui.Error("Expected at most one file to import")
+		printImportUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		handleCommandError("Import", fmt.Errorf("cannot get current directory: %v", err), ExitAgentError)
+	}
+
+	format := *formatFlag
+	if format == "" && len(positional) == 1 {
+		format = core.DetectImportFormat(positional[0])
+	}
+	if format == "" && len(positional) == 0 {
+		// Without a format, take the one instructions file the project has
+		var found []string
+		for _, candidate := range core.ImportFormats {
+			if _, err := core.ImportSourcePaths(currentDir, candidate); err == nil {
+				found = append(found, candidate)
+			}
+		}
+		if len(found) == 1 {
+			format = found[0]
+		} else if len(found) > 1 {
+			ui.Error("Instructions found for several formats (%s); choose one with --format", strings.Join(found, ", "))
+			os.Exit(ExitUsageError)
+		}
+	}
+	if format == "" {
+		ui.Error("Missing --format")
+		printImportUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	configManager := utils.NewConfigManager()
+	if err := configManager.Load(); err != nil {
+		handleCommandError("Import", fmt.Errorf("cannot load configuration: %v", err), ExitAgentError)
+	}
+	config := configManager.GetConfig()
+
+	// The format still has to be known when a file is given, as it names the conventions to follow
+	sources, err := core.ImportSourcePaths(currentDir, format)
+	if len(positional) == 1 {
+		if _, isValidation := err.(*core.ValidationError); isValidation {
+			handleCommandError("Import", err, ExitUsageError)
+		}
+		sources, err = []string{positional[0]}, nil
+	}
+	if err != nil {
+		handleCommandError("Import", err, ExitUsageError)
+	}
+
+	imported, err := core.ImportAgents(sources, *alwaysFlag)
+	if err != nil {
+		handleCommandError("Import", err, ExitAgentError)
+	}
+	if len(imported) == 0 {
+		handleCommandError("Import", fmt.Errorf("no instructions found in %s", strings.Join(sources, ", ")), ExitAgentError)
+	}
+
+	registry, err := agent.NewRegistry(config, projectRulesDir(config, currentDir))
+	if err != nil {
+		handleCommandError("Import", err, ExitAgentError)
+	}
+
+	var existing []string
+	for _, definition := range imported {
+		if registry.AgentExists(definition.ID) {
+			existing = append(existing, definition.ID)
+		}
+	}
+	if len(existing) > 0 && !*forceFlag {
+		handleCommandError("Import", fmt.Errorf("agents already installed: %s; use --force to replace them",
+			strings.Join(existing, ", ")), ExitAgentError)
+	}
+
+	// Every agent is checked before any is written, so a bad one cannot leave the import half done
+	store := agent.NewFileRegistry(registry)
+	configs := make([]*agent.AgentConfig, len(imported))
+	for i, definition := range imported {
+		configs[i] = &agent.AgentConfig{
+			Metadata: agent.AgentMetadata{ID: definition.ID},
+			Settings: map[string]interface{}{agent.SettingContent: definition.Content},
+		}
+		if _, _, err := store.PrepareAgent(configs[i]); err != nil {
+			handleCommandError("Import", fmt.Errorf("%s:%d: %v", displayPath(currentDir, definition.Source), definition.Line, err), ExitAgentError)
+		}
+	}
+
+	fmt.Println()
+	for i, definition := range imported {
+		path := displayPath(currentDir, filepath.Join(registry.GetRulesDir(), definition.ID+".mdc"))
+		source := ui.InfoStyle.Sprintf("(from %s:%d, %s)", displayPath(currentDir, definition.Source), definition.Line, importedAttachMode(definition))
+		action, done := "create ", "created"
+		if registry.AgentExists(definition.ID) {
+			action, done = "replace", "replaced"
+		}
+		if *dryRunFlag {
+			ui.Plain("  %s %s %s", action, path, source)
+			continue
+		}
+
+		// Installed agents are only replaced once their new file has been written
+		if err := store.ReplaceAgent(configs[i]); err != nil {
+			handleCommandError("Import", err, ExitAgentError)
+		}
+		ui.Plain("  %-8s %s %s", done, path, source)
+	}
+	fmt.Println()
+
+	if *dryRunFlag {
+		ui.Info("Dry run: nothing was written")
+		return
+	}
+	ui.Success("Imported %d agents into %s", len(imported), displayPath(currentDir, registry.GetRulesDir()))
+	ui.Plain("Review their descriptions and globs, then check them with cursor++ agent lint")
+}
+
+// importedAttachMode names when Cursor will include an imported agent
+func importedAttachMode(imported core.ImportedAgent) string {
+	definition := agent.AgentDefinition{
+		Globs:                  imported.Globs,
+		AlwaysApply:            imported.AlwaysApply,
+		FrontmatterDescription: imported.Description,
+		HasFrontmatter:         true,
+	}
+	return definition.AttachMode()
+}
+
+func printImportUsage() {
+	ui.Header("Usage: cursor++ import --format <format> [file] [OPTIONS]")
+
+	ui.Plain("\nSplits the instruction file of another AI assistant into one .mdc agent per topic,")
+	ui.Plain("by heading, and installs them into the project's rules directory:")
+	ui.Plain("  copilot      .github/copilot-instructions.md and .github/instructions/*.instructions.md")
+	ui.Plain("  agents-md    AGENTS.md")
+	ui.Plain("  windsurf     .windsurfrules")
+	ui.Plain("  cline        .clinerules, a file or a directory of Markdown files")
+	ui.Plain("  cursorrules  .cursorrules, Cursor's legacy single rules file")
+	ui.Plain("The file defaults to the format's usual location in the current directory. The")
+	ui.Plain("format can be left out when the file has its usual name, or when the project has")
+	ui.Plain("instructions for only one format.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --format <format>  Format to import from")
+	ui.Plain("  --always-apply     Include the imported agents without globs in every request")
+	ui.Plain("                     (default: Cursor picks them by their description)")
+	ui.Plain("  --force            Replace installed agents with the same IDs")
+	ui.Plain("  --dry-run          List the agents that would be created without writing them")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ import --format cursorrules")
+	ui.Plain("  cursor++ import --format copilot --dry-run")
+	ui.Plain("  cursor++ import ../other-repo/AGENTS.md")
+}
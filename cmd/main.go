@@ -27,6 +27,9 @@ const (
 	ExitAgentError  = 15
 	ExitSetupError  = 20
 	ExitConfigError = 25
+	ExitUpdateError = 30
+	ExitRemoveError = 35
+	ExitLintError   = 40
 )
 
 // getTerminalWidth returns the width of the terminal in characters
@@ -108,8 +111,26 @@ func main() {
 		os.Exit(ExitUsageError)
 	}
 
-	// Display banner for all commands
-	ui.PrintBanner()
+	var initializer *core.AgentInitializer
+	commands := map[string]command{
+		"init":   {run: func(args []string) { handleInit(initializer, args) }},
+		"update": {run: func(args []string) { handleUpdate(initializer) }},
+		"remove": {run: func(args []string) { handleRemove(initializer, args) }},
+		"source": {run: handleSource},
+		"agent": {
+			run:             func(args []string) { handleAgent(initializer, appPaths, *verboseFlag, args) },
+			machineReadable: agentMachineReadable,
+		},
+		"rules":  {run: handleRules, machineReadable: rulesMachineReadable},
+		"export": {run: handleExport, machineReadable: machineReadableWith(newExportFlags)},
+		"import": {run: handleImport},
+	}
+	command, known := commands[args[0]]
+
+	// Display banner for all commands, unless their output is meant for other tools
+	if command.machineReadable == nil || !command.machineReadable(args[1:]) {
+		ui.PrintBanner()
+	}
 
 	// Create new sync manager
 	utils.Debug("Initializing sync manager")
@@ -143,22 +164,24 @@ func main() {
 	}
 
 	// Handle commands
-	command := args[0]
-	utils.Info("Executing command | command=" + command)
-
-	switch command {
-	case "init":
-		handleInit(initializer)
-	case "agent":
-		handleAgent(initializer, appPaths, *verboseFlag, args[1:])
-	default:
-		utils.Warn("Unknown command received | command=" + command)
-		ui.Warning("Unknown command: %s", command)
+	utils.Info("Executing command | command=" + args[0])
+	if !known {
+		utils.Warn("Unknown command received | command=" + args[0])
+		ui.Warning("Unknown command: %s", args[0])
 		printUsage()
 		os.Exit(ExitUsageError)
 	}
+	command.run(args[1:])
 
-	utils.Info("Command completed successfully | command=" + command)
+	utils.Info("Command completed successfully | command=" + args[0])
+}
+
+// command is a top-level command of the CLI
+type command struct {
+	run func(args []string)
+	// machineReadable reports whether the command's arguments ask for output meant for other
+	// tools, such as JSON, so nothing else may be printed to standard output; nil if it never does
+	machineReadable func(args []string) bool
 }
 
 // handleCommandError handles command errors consistently
@@ -199,16 +222,58 @@ func printUsage() {
 
 	ui.Plain("\nCommands:")
 	ui.Plain("  init         Initialize current directory with cursor++ agents")
+	ui.Plain("  update       Re-sync agents in every project initialized with cursor++")
+	ui.Plain("  remove       Remove the agents cursor++ installed into the current directory")
+	ui.Plain("  source       Manage the rule sources agents are installed from")
 	ui.Plain("  agent        Interactively select and use agents for cursor++ IDE")
+	ui.Plain("  rules        Inspect how the installed rules apply to the project")
+	ui.Plain("  export       Convert the installed agents for other AI assistants")
+	ui.Plain("  import       Split other AI assistants' instructions into agents")
 }
 
-func handleInit(manager *core.AgentInitializer) {
+func handleInit(manager *core.AgentInitializer, args []string) {
 	utils.Debug("Handling init command")
 
+	fs := newCommandFlags("init", printInitUsage)
+	lockedFlag := fs.Bool("locked", false, "Install exactly the commit pinned in .cursor/cursor++.lock")
+	fromFlag := fs.String("from", "", "Install from a local directory, file:// git URL or .tar.gz/.zip archive")
+	dryRunFlag := fs.Bool("dry-run", false, "Print the planned file changes without writing anything")
+	agentsFlag := fs.String("agents", "", "Comma separated agent IDs to install")
+	excludeFlag := fs.String("exclude", "", "Comma separated agent IDs not to install")
+	categoryFlag := fs.String("category", "", "Comma separated agent categories to install")
+	tagFlag := fs.String("tag", "", "Comma separated agent tags to install")
+	allFlag := fs.Bool("all", false, "Install all agents and forget the remembered selection")
+	parseCommandFlags(fs, args)
+
+	opts := core.InitOptions{
+		Locked: *lockedFlag,
+		From:   *fromFlag,
+	}
+
+	selection := &core.Selection{
+		Agents:     splitList(*agentsFlag),
+		Exclude:    splitList(*excludeFlag),
+		Categories: splitList(*categoryFlag),
+		Tags:       splitList(*tagFlag),
+	}
+	if *allFlag && !selection.IsEmpty() {
+		ui.Error("--all cannot be combined with --agents, --exclude, --category or --tag")
+		printInitUsage()
+		os.Exit(ExitUsageError)
+	}
+	if *allFlag || !selection.IsEmpty() {
+		opts.Selection = selection
+	}
+
+	if *dryRunFlag {
+		handleInitDryRun(manager, opts)
+		return
+	}
+
 	// Print a blank line before starting for better spacing
 	fmt.Println()
 
-	if err := manager.Init(); err != nil {
+	if err := manager.Init(opts); err != nil {
 		handleCommandError("Init", err, ExitInitError)
 	}
 
@@ -273,6 +338,98 @@ func handleInit(manager *core.AgentInitializer) {
 	utils.Info("Init command completed successfully")
 }
 
+// handleInitDryRun prints the changes init would make without touching the project
+func handleInitDryRun(manager *core.AgentInitializer, opts core.InitOptions) {
+	fmt.Println()
+
+	plan, err := manager.PlanInit(opts)
+	if err != nil {
+		handleCommandError("Init", err, ExitInitError)
+	}
+
+	rows := make([]ui.FilePlanRow, 0, len(plan.Files))
+	for _, file := range plan.Files {
+		details := ""
+		switch file.Action {
+		case core.ActionCreate:
+			details = fmt.Sprintf("+%d", file.Added)
+		case core.ActionOverwrite, core.ActionMerge, core.ActionBackup:
+			details = fmt.Sprintf("+%d -%d", file.Added, file.Removed)
+		case core.ActionConflict:
+			details = fmt.Sprintf("+%d -%d, %d conflict(s)", file.Added, file.Removed, file.Conflicts)
+		case core.ActionKeepLocal:
+			details = "local edits kept"
+		case core.ActionSkip:
+			details = "not an .mdc file"
+		case core.ActionRemove:
+			details = fmt.Sprintf("-%d", file.Removed)
+		case core.ActionDetach:
+			details = "no longer installed, local edits kept"
+		}
+		rows = append(rows, ui.FilePlanRow{
+			Path:    file.Path,
+			Action:  string(file.Action),
+			Details: details,
+		})
+	}
+
+	ui.Header("Dry run for %s (sources: %s):", plan.Project, strings.Join(plan.Sources, ", "))
+	ui.DisplayFilePlan(rows)
+	fmt.Println()
+
+	if plan.Register {
+		ui.Info("The project would be added to the cursor++ registry")
+	} else {
+		ui.Info("The project is already in the cursor++ registry")
+	}
+	ui.Plain("No files were changed. Run %s to apply this plan.", ui.SuccessStyle.Sprint("cursor++ init"))
+	fmt.Println()
+
+	utils.Info("Init dry run completed")
+}
+
+func printInitUsage() {
+	ui.Header("Usage: cursor++ init [OPTIONS]")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --locked        Install exactly the commit pinned in .cursor/cursor++.lock")
+	ui.Plain("  --from <src>    Install offline from a local directory, file:// git URL or .tar.gz/.zip archive")
+	ui.Plain("  --dry-run       Print the planned file changes without writing anything")
+	ui.Plain("  --agents <ids>  Only install these comma separated agent IDs")
+	ui.Plain("  --exclude <ids> Never install these comma separated agent IDs")
+	ui.Plain("  --category <c>  Only install agents in these comma separated categories")
+	ui.Plain("  --tag <tags>    Only install agents with these comma separated tags")
+	ui.Plain("  --all           Install all agents and forget the remembered selection")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ init            # Install the latest cached agents and write the lockfile")
+	ui.Plain("  cursor++ init --locked   # Reproduce the install recorded in the lockfile")
+	ui.Plain("  cursor++ init --from ./rules.tar.gz   # Install without network access")
+	ui.Plain("  cursor++ init --dry-run  # Review what init would change")
+	ui.Plain("  cursor++ init --exclude scraper-planner,git-committer")
+	ui.Plain("  cursor++ init --category testing --agents wizard")
+}
+
+// agentOutputs holds, for each agent subcommand that can print output meant for other tools,
+// whether its arguments ask for it
+var agentOutputs = map[string]func(args []string) bool{
+	"lint":   machineReadableWith(newAgentLintFlags),
+	"graph":  machineReadableWith(newAgentGraphFlags),
+	"render": machineReadableWith(newAgentRenderFlags),
+	"search": machineReadableWith(newAgentSearchFlags),
+	"which": machineReadableWith(func() (*flag.FlagSet, *agentWhichFlags) {
+		return newAgentWhichFlags(0)
+	}),
+}
+
+// agentMachineReadable reports whether an agent subcommand is asked for output meant for other tools
+func agentMachineReadable(args []string) bool {
+	if len(args) == 0 || agentOutputs[args[0]] == nil {
+		return false
+	}
+	return agentOutputs[args[0]](args[1:])
+}
+
 func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbose bool, args []string) {
 	utils.Debug("Handling agent command")
 
@@ -292,6 +449,17 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 	// Try multiple possible locations for agent definitions
 	rulesDir := filepath.Join(currentDir, config.RulesDirName)
 
+	// Linting reads files directly, so it works on broken rules and prints nothing but its report
+	if len(args) > 0 && args[0] == "lint" {
+		handleAgentLint(rulesDir, args[1:])
+		return
+	}
+	// The graph may be drawn for any rules directory, such as the bundled rules
+	if len(args) > 0 && args[0] == "graph" {
+		handleAgentGraph(config, rulesDir, args[1:])
+		return
+	}
+
 	// First location: .cursor/rules/cursor-rules (AgentsDirName subfolder)
 	localRulesDir := filepath.Join(rulesDir, config.AgentsDirName)
 
@@ -352,6 +520,11 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 		}
 	}
 
+	// Agents can be added to a project without any yet
+	if chosenDir == "" && len(args) > 0 && (args[0] == "add" || args[0] == "new") {
+		chosenDir = directRulesDir
+	}
+
 	// Check if we found a valid directory
 	if chosenDir == "" {
 		ui.Warning("No local agent definitions found in %s or %s", localRulesDir, directRulesDir)
@@ -399,7 +572,7 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 		if utils.IsVerbose() {
 			utils.Info("No subcommand provided, displaying agent list")
 		}
-		displayAgentList(registry)
+		displayAgentList(registry, false)
 		return
 	}
 
@@ -412,7 +585,10 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 		if utils.IsVerbose() {
 			utils.Info("Displaying agent list")
 		}
-		displayAgentList(registry)
+		fs := newCommandFlags("agent list", printAgentUsage)
+		longFlag := fs.Bool("long", false, "Show details and the estimated token count of each agent")
+		parseCommandFlags(fs, subcommandArgs(args, subCommand))
+		displayAgentList(registry, *longFlag)
 	case "select":
 		if utils.IsVerbose() {
 			utils.Info("Entering agent selection mode")
@@ -426,7 +602,19 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 		if utils.IsVerbose() {
 			utils.Infof("Displaying agent info for: %s", filteredArgs[1])
 		}
-		handleAgentInfo(registry, filteredArgs[1], verbose)
+		handleAgentInfo(registry, filteredArgs[1], verbose, config.TokenBudget)
+	case "add":
+		handleAgentAdd(registry, subcommandArgs(args, subCommand))
+	case "remove", "rm":
+		handleAgentRemove(manager, registry, subcommandArgs(args, subCommand))
+	case "new":
+		handleAgentNew(registry, subcommandArgs(args, subCommand))
+	case "render":
+		handleAgentRender(manager, registry, subcommandArgs(args, subCommand))
+	case "search":
+		handleAgentSearch(registry, subcommandArgs(args, subCommand))
+	case "which":
+		handleAgentWhich(config, registry, subcommandArgs(args, subCommand))
 	case "help", "--help", "-h":
 		if utils.IsVerbose() {
 			utils.Info("Displaying agent usage help")
@@ -439,8 +627,8 @@ func handleAgent(manager *core.AgentInitializer, appPaths utils.AppPaths, verbos
 	}
 }
 
-// displayAgentList shows available agents
-func displayAgentList(registry *agent.Registry) {
+// displayAgentList shows available agents; the long form shows details and token estimates
+func displayAgentList(registry *agent.Registry, long bool) {
 	if utils.IsDebug() {
 		utils.Debug("Displaying agent list | registry path: " + registry.GetRulesDir())
 	}
@@ -504,6 +692,10 @@ func displayAgentList(registry *agent.Registry) {
 	options.GroupByCategory = termWidth > 100 // Use categories for wider terminals
 	options.CompactMode = termWidth < 80
 	options.SelectedAgentID = lastSelectedAgent
+	if long {
+		options.CompactMode = false
+		options.ShowTokens = true
+	}
 
 	if utils.IsDebug() {
 		utils.Debugf("Agent display options: %+v", options)
@@ -515,6 +707,20 @@ func displayAgentList(registry *agent.Registry) {
 	// Display with enhanced UI
 	ui.DisplayAgentListEnhanced(agents, options)
 
+	if long {
+		alwaysTokens := 0
+		for _, a := range agents {
+			if a.AlwaysApply {
+				alwaysTokens += a.Tokens
+			}
+		}
+		ui.Info("Rules attached to every file (alwaysApply) use %s together", ui.FormatTokens(alwaysTokens))
+		if config.TokenBudget > 0 && alwaysTokens > config.TokenBudget {
+			ui.Warning("That is over the token budget of %s before any glob-attached rule is added",
+				ui.FormatTokens(config.TokenBudget))
+		}
+	}
+
 	// Add usage hint after the list
 	ui.Plain("\nTip: Use %s to get detailed information about a specific agent",
 		ui.SuccessStyle.Sprint("cursor++ agent info <agent-id>"))
@@ -789,7 +995,7 @@ func handleAgentSelect(registry *agent.Registry, config *utils.Config, appPaths
 	utils.Info("Agent select completed successfully | selected_agent=" + selectedAgent.ID)
 }
 
-func handleAgentInfo(registry *agent.Registry, agentParam string, verbose bool) {
+func handleAgentInfo(registry *agent.Registry, agentParam string, verbose bool, tokenBudget int) {
 	if utils.IsDebug() {
 		utils.Debugf("Handling agent info subcommand | agent_param=%s verbose=%t", agentParam, verbose)
 	}
@@ -874,6 +1080,9 @@ func handleAgentInfo(registry *agent.Registry, agentParam string, verbose bool)
 	if err != nil {
 		handleCommandError("Agent display", err, ExitAgentError)
 	}
+	if tokenBudget > 0 && agentDef.Tokens > tokenBudget {
+		ui.Warning("%s alone is over the token budget of %s", agentDef.ID, ui.FormatTokens(tokenBudget))
+	}
 
 	if utils.IsVerbose() {
 		utils.Infof("Agent info displayed successfully | agent=%s (%s)", agentDef.Name, agentDef.ID)
@@ -889,9 +1098,17 @@ func printAgentUsage() {
 
 	ui.Plain("\nSubcommands:")
 	ui.Plain("  <none>       List all available agents (default)")
-	ui.Plain("  list         List all available agents")
+	ui.Plain("  list [--long]  List all available agents, with details and token estimates")
 	ui.Plain("  select       Interactively select an agent")
 	ui.Plain("  info <id>    Display detailed information about a specific agent")
+	ui.Plain("  add <file|url>  Add an agent definition to the project")
+	ui.Plain("  remove <id>  Delete an agent from the project")
+	ui.Plain("  new [id]     Create a new agent from the standard skeleton")
+	ui.Plain("  lint [dir]   Check agent definitions for problems")
+	ui.Plain("  render <id>  Render one of an agent's templates")
+	ui.Plain("  graph [dir]  Show which agents hand off to which, as Mermaid or DOT")
+	ui.Plain("  search <query>  Search agent names, descriptions and content")
+	ui.Plain("  which <path>    Show which rules attach to a file and why")
 	ui.Plain("  help         Show this help message")
 
 	ui.Plain("\nExample usage:")
@@ -900,5 +1117,6 @@ func printAgentUsage() {
 	ui.Plain("  cursor++ agent select          # Interactively select an agent")
 	ui.Plain("  cursor++ agent info wizard     # Show info about the wizard agent")
 	ui.Plain("  cursor++ agent info 1 --debug  # Show detailed info with debug output")
+	ui.Plain("  cursor++ agent add ./team-reviewer.mdc  # Add a custom agent")
 	ui.Plain("\nYou can also reference agents in the chatbox using @ (example: @wizard.mdc)")
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+func handleRemove(manager *core.AgentInitializer, args []string) {
+	utils.Debug("Handling remove command")
+
+	fs := newCommandFlags("remove", printRemoveUsage)
+	forceFlag := fs.Bool("force", false, "Also delete installed agent files you edited")
+	parseCommandFlags(fs, args)
+
+	fmt.Println()
+
+	result, err := manager.Remove(core.RemoveOptions{Force: *forceFlag})
+	if err != nil {
+		handleCommandError("Remove", err, ExitRemoveError)
+	}
+
+	if !result.Tracked {
+		ui.Warning("No install manifest found in %s; no agent files were removed", result.Project)
+	}
+
+	for _, path := range result.Removed {
+		ui.Plain("  removed  %s", path)
+	}
+	for _, path := range result.Kept {
+		ui.Plain("  kept     %s %s", path, ui.WarnStyle.Sprint("(edited locally)"))
+	}
+	if len(result.Removed) > 0 || len(result.Kept) > 0 {
+		fmt.Println()
+	}
+
+	if len(result.Kept) > 0 {
+		ui.Warning("%d edited file(s) were kept; use %s to delete them as well",
+			len(result.Kept), ui.SuccessStyle.Sprint("cursor++ remove --force"))
+	}
+	if result.Unregistered {
+		ui.Info("Project removed from the cursor++ registry")
+	}
+
+	ui.Success("Removed %d agent file(s) from %s", len(result.Removed), result.Project)
+	fmt.Println()
+
+	utils.Info("Remove command completed successfully")
+}
+
+func printRemoveUsage() {
+	ui.Header("Usage: cursor++ remove [OPTIONS]")
+
+	ui.Plain("\nRemoves the agent files cursor++ installed into the current project, its")
+	ui.Plain("bookkeeping and lockfile, and unregisters the project. Rules you wrote are never touched.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --force      Also delete installed agent files you edited")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ remove           # Remove installed agents, keeping edited ones")
+	ui.Plain("  cursor++ remove --force   # Remove every installed agent")
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+func handleRules(args []string) {
+	utils.Debug("Handling rules command")
+
+	if len(args) == 0 {
+		printRulesUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	switch args[0] {
+	case "coverage":
+		handleRulesCoverage(args[1:])
+	case "duplicates":
+		handleRulesDuplicates(args[1:])
+	case "help", "--help", "-h":
+		printRulesUsage()
+	default:
+		ui.Warning("Unknown rules subcommand: %s", args[0])
+		printRulesUsage()
+		os.Exit(ExitUsageError)
+	}
+}
+
+// rulesOutputs holds, for each rules subcommand that can print output meant for other tools,
+// whether its arguments ask for it
+var rulesOutputs = map[string]func(args []string) bool{
+	"coverage":   machineReadableWith(newRulesCoverageFlags),
+	"duplicates": machineReadableWith(newRulesDuplicatesFlags),
+}
+
+// rulesMachineReadable reports whether a rules subcommand is asked for output meant for other tools
+func rulesMachineReadable(args []string) bool {
+	if len(args) == 0 || rulesOutputs[args[0]] == nil {
+		return false
+	}
+	return rulesOutputs[args[0]](args[1:])
+}
+
+// loadProjectRegistry loads the rules installed in the current directory, see projectRulesDir.
+// A non-empty rulesDir loads that directory instead.
+func loadProjectRegistry(commandName, rulesDir string) (*agent.Registry, string) {
+	configManager := utils.NewConfigManager()
+	if err := configManager.Load(); err != nil {
+		handleCommandError(commandName, fmt.Errorf("cannot load configuration: %v", err), ExitAgentError)
+	}
+	config := configManager.GetConfig()
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		handleCommandError(commandName, fmt.Errorf("cannot get current directory: %v", err), ExitAgentError)
+	}
+
+	if rulesDir == "" {
+		rulesDir = projectRulesDir(config, currentDir)
+	}
+	if hasMDC, _ := utils.HasMDCFiles(rulesDir); !hasMDC {
+		handleCommandError(commandName, fmt.Errorf("no rules installed in %s; run cursor++ init first", rulesDir), ExitAgentError)
+	}
+
+	registry, err := agent.NewRegistry(config, rulesDir)
+	if err != nil {
+		handleCommandError(commandName, err, ExitAgentError)
+	}
+	return registry, currentDir
+}
+
+// projectRulesDir returns where the agents of a project live: the agents subfolder of its rules
+// directory if it holds any, and the rules directory itself otherwise
+func projectRulesDir(config *utils.Config, projectDir string) string {
+	rulesDir := filepath.Join(projectDir, config.RulesDirName)
+	if subfolder := filepath.Join(rulesDir, config.AgentsDirName); utils.DirExists(subfolder) {
+		if hasMDC, _ := utils.HasMDCFiles(subfolder); hasMDC {
+			return subfolder
+		}
+	}
+	return rulesDir
+}
+
+// rulesCoverageFlags holds the flags of rules coverage
+type rulesCoverageFlags struct {
+	json  *bool
+	depth *int
+	limit *int
+}
+
+// newRulesCoverageFlags defines the flags of rules coverage
+func newRulesCoverageFlags() (*flag.FlagSet, *rulesCoverageFlags) {
+	fs := newCommandFlags("rules coverage", printRulesCoverageUsage)
+	return fs, &rulesCoverageFlags{
+		json:  fs.Bool("json", false, "Print the report as JSON"),
+		depth: fs.Int("depth", 2, "Directory depth to summarize at; 0 lists every directory"),
+		limit: fs.Int("limit", 20, "Files without rules to list; 0 lists all"),
+	}
+}
+
+// machineReadable reports whether the report is printed as JSON
+func (f *rulesCoverageFlags) machineReadable() bool {
+	return *f.json
+}
+
+func handleRulesCoverage(args []string) {
+	fs, options := newRulesCoverageFlags()
+	parseCommandFlags(fs, args)
+
+	registry, projectDir := loadProjectRegistry("Rules coverage", "")
+	report, err := core.RulesCoverage(registry, projectDir, *options.depth)
+	if err != nil {
+		handleCommandError("Rules coverage", err, ExitAgentError)
+	}
+
+	if *options.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			handleCommandError("Rules coverage", err, ExitAgentError)
+		}
+		return
+	}
+
+	ui.Header("\nRules by matched files")
+	ruleRows := make([]ui.RuleCoverageRow, 0, len(report.Rules))
+	for _, rule := range report.Rules {
+		ruleRows = append(ruleRows, ui.RuleCoverageRow{ID: rule.ID, Globs: strings.Join(rule.Globs, ", "), Files: rule.Files})
+	}
+	ui.DisplayRuleCoverage(ruleRows)
+
+	ui.Header("\nRules by directory")
+	dirRows := make([]ui.DirectoryCoverageRow, 0, len(report.Directories))
+	for _, dir := range report.Directories {
+		dirRows = append(dirRows, ui.DirectoryCoverageRow{Path: dir.Path, Files: dir.Files, Uncovered: dir.Uncovered, Rules: dir.Rules})
+	}
+	ui.DisplayDirectoryCoverage(dirRows)
+
+	if len(report.UncoveredFiles) > 0 {
+		ui.Header("\nFiles without rules")
+		shown := report.UncoveredFiles
+		if *options.limit > 0 && len(shown) > *options.limit {
+			shown = shown[:*options.limit]
+		}
+		for _, file := range shown {
+			ui.Plain("  %s", file)
+		}
+		if len(shown) < len(report.UncoveredFiles) {
+			ui.Plain("  ... and %d more; use --limit 0 or --json to see all", len(report.UncoveredFiles)-len(shown))
+		}
+	}
+
+	fmt.Println()
+	percent := 0.0
+	if report.Files > 0 {
+		percent = float64(report.Covered) * 100 / float64(report.Files)
+	}
+	ui.Info("%d of %d files (%.0f%%) match the globs of at least one rule", report.Covered, report.Files, percent)
+	if len(report.AlwaysRules) > 0 {
+		ui.Info("Attached to every file through alwaysApply: %s", strings.Join(report.AlwaysRules, ", "))
+	}
+	for _, id := range report.UnusedRules {
+		ui.Warning("%s has globs but matches no files", id)
+	}
+}
+
+func printRulesUsage() {
+	ui.Header("Usage: cursor++ rules <subcommand>")
+
+	ui.Plain("\nSubcommands:")
+	ui.Plain("  coverage     Report which files the rules' globs reach")
+	ui.Plain("  duplicates   Find paragraphs and list items copied between agents")
+	ui.Plain("  help         Show this help message")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ rules coverage")
+	ui.Plain("  cursor++ rules coverage --depth 1 --json")
+	ui.Plain("  cursor++ rules duplicates")
+}
+
+func printRulesCoverageUsage() {
+	ui.Header("Usage: cursor++ rules coverage [OPTIONS]")
+
+	ui.Plain("\nMatches the globs of the installed rules against every file of the project,")
+	ui.Plain("skipping files ignored by git. Reports how many files each rule matches, the")
+	ui.Plain("rules matching no files, the files no rule matches and the rules per directory.")
+	ui.Plain("Rules with alwaysApply attach to every file and are listed separately.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --depth <n>  Directory depth to summarize at (default 2, 0 for every directory)")
+	ui.Plain("  --limit <n>  Files without rules to list (default 20, 0 for all)")
+	ui.Plain("  --json       Print the report as JSON")
+}
+
+// rulesDuplicatesFlags holds the flags of rules duplicates
+type rulesDuplicatesFlags struct {
+	json     *bool
+	distance *int
+	minWords *int
+}
+
+// newRulesDuplicatesFlags defines the flags of rules duplicates
+func newRulesDuplicatesFlags() (*flag.FlagSet, *rulesDuplicatesFlags) {
+	fs := newCommandFlags("rules duplicates", printRulesDuplicatesUsage)
+	return fs, &rulesDuplicatesFlags{
+		json:     fs.Bool("json", false, "Print the report as JSON"),
+		distance: fs.Int("max-distance", core.DefaultDuplicateDistance, "Differing simhash bits up to which blocks are near duplicates"),
+		minWords: fs.Int("min-words", core.DefaultDuplicateMinWords, "Ignore paragraphs and list items with fewer words"),
+	}
+}
+
+// machineReadable reports whether the report is printed as JSON
+func (f *rulesDuplicatesFlags) machineReadable() bool {
+	return *f.json
+}
+
+func handleRulesDuplicates(args []string) {
+	fs, options := newRulesDuplicatesFlags()
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) > 1 {
+		ui.Error("Expected at most one rules directory")
+		printRulesDuplicatesUsage()
+		os.Exit(ExitUsageError)
+	}
+	if *options.distance < 0 || *options.distance > 64 {
+		ui.Error("--max-distance must be between 0 and 64")
+		os.Exit(ExitUsageError)
+	}
+
+	rulesDir := ""
+	if len(positional) == 1 {
+		rulesDir = positional[0]
+	}
+	registry, projectDir := loadProjectRegistry("Rules duplicates", rulesDir)
+
+	report, err := core.FindDuplicates(registry, *options.distance, *options.minWords)
+	if err != nil {
+		handleCommandError("Rules duplicates", err, ExitAgentError)
+	}
+
+	if *options.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			handleCommandError("Rules duplicates", err, ExitAgentError)
+		}
+		return
+	}
+
+	for _, group := range report.Groups {
+		agents := make(map[string]bool)
+		for _, block := range group.Blocks {
+			agents[block.Agent] = true
+		}
+
+		fmt.Println()
+		if group.Identical {
+			ui.Header("Identical in %d agents, %s in extra copies", len(agents), ui.FormatTokens(group.WastedTokens))
+			ui.Plain("  %s", ui.WarnStyle.Sprint(shortenText(group.Blocks[0].Text, 100)))
+		} else {
+			ui.Header("Nearly identical in %d agents (distance %d), %s in extra copies",
+				len(agents), group.Distance, ui.FormatTokens(group.WastedTokens))
+		}
+		for _, block := range group.Blocks {
+			location := fmt.Sprintf("%s:%d", displayPath(projectDir, block.File), block.Line)
+			if block.Section != "" {
+				location += ui.InfoStyle.Sprintf(" (%s)", block.Section)
+			}
+			ui.Plain("  %s", location)
+			if !group.Identical {
+				ui.Plain("      %s", shortenText(block.Text, 100))
+			}
+		}
+	}
+
+	fmt.Println()
+	if len(report.Groups) == 0 {
+		ui.Success("No duplicated content in %d agents", report.Agents)
+		return
+	}
+	ui.Warning("%d duplicated passages across %d agents, %s in extra copies",
+		len(report.Groups), report.Agents, ui.FormatTokens(report.WastedTokens))
+}
+
+// shortenText cuts text to at most width characters, marking the cut with an ellipsis
+func shortenText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// displayPath shows a path relative to dir when it is inside it
+func displayPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func printRulesDuplicatesUsage() {
+	ui.Header("Usage: cursor++ rules duplicates [dir] [OPTIONS]")
+
+	ui.Plain("\nCompares the paragraphs and list items of every installed agent with those of the")
+	ui.Plain("other agents and reports the ones copied between them, exactly or with small edits,")
+	ui.Plain("with their locations. Near duplicates are found by similarity hashing (simhash).")
+	ui.Plain("The directory defaults to the project's rules.")
+
+	ui.Plain("\nOptions:")
+	ui.Plain("  --max-distance <n>  Differing hash bits up to which passages count as near")
+	ui.Plain("                      duplicates (default 8); lower finds only closer copies")
+	ui.Plain("  --min-words <n>     Ignore passages with fewer words (default 8)")
+	ui.Plain("  --json              Print the report as JSON")
+}
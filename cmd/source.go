@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+func handleSource(args []string) {
+	utils.Debug("Handling source command")
+
+	if len(args) == 0 {
+		handleSourceList()
+		return
+	}
+
+	switch args[0] {
+	case "list":
+		handleSourceList()
+	case "add":
+		handleSourceAdd(args[1:])
+	case "remove", "rm":
+		handleSourceRemove(args[1:])
+	case "help", "--help", "-h":
+		printSourceUsage()
+	default:
+		ui.Warning("Unknown source subcommand: %s", args[0])
+		printSourceUsage()
+		os.Exit(ExitUsageError)
+	}
+}
+
+// loadSourceConfig loads the configuration for editing its rule sources.
+// The implicit default source is made explicit so it can be reprioritized or removed.
+func loadSourceConfig() (*utils.ConfigManager, *utils.Config) {
+	cm := utils.NewConfigManager()
+	if err := cm.Load(); err != nil {
+		handleCommandError("Source", err, ExitConfigError)
+	}
+	config := cm.GetConfig()
+	if len(config.Sources) == 0 {
+		config.Sources = []utils.RuleSource{config.DefaultRuleSource()}
+	}
+	return cm, config
+}
+
+// saveSourceConfig persists changes to the rule sources
+func saveSourceConfig(cm *utils.ConfigManager, config *utils.Config) {
+	cm.SetConfig(config)
+	if err := cm.Save(); err != nil {
+		handleCommandError("Source", err, ExitConfigError)
+	}
+}
+
+func handleSourceList() {
+	cm := utils.NewConfigManager()
+	if err := cm.Load(); err != nil {
+		handleCommandError("Source", err, ExitConfigError)
+	}
+	config := cm.GetConfig()
+
+	fmt.Println()
+	ui.Header("Rule Sources:")
+	ui.DisplaySourceTable(config.GetSources())
+	ui.Plain("\nHigher priority sources replace agents with the same ID from lower priority sources.")
+	fmt.Println()
+}
+
+func handleSourceAdd(args []string) {
+	fs := newCommandFlags("source add", printSourceUsage)
+	nameFlag := fs.String("name", "", "Name of the source (defaults to the repository or directory name)")
+	refFlag := fs.String("ref", "", "Branch, tag or commit to check out")
+	subfolderFlag := fs.String("subfolder", "", "Folder inside the source holding the rules")
+	priorityFlag := fs.Int("priority", 0, "Priority of the source; higher wins on agent ID clashes")
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) != 1 {
+		ui.Error("source add requires exactly one URL or path")
+		printSourceUsage()
+		os.Exit(ExitUsageError)
+	}
+
+	source := utils.RuleSource{
+		Name:      *nameFlag,
+		Ref:       *refFlag,
+		Subfolder: *subfolderFlag,
+		Priority:  *priorityFlag,
+	}
+
+	location := positional[0]
+	if isRemoteLocation(location) {
+		source.URL = location
+	} else {
+		absPath, err := filepath.Abs(location)
+		if err != nil {
+			handleCommandError("Source", err, ExitUsageError)
+		}
+		if !utils.DirExists(absPath) {
+			ui.Error("Source directory does not exist: %s", absPath)
+			os.Exit(ExitUsageError)
+		}
+		if source.Ref != "" {
+			ui.Error("--ref can only be used with git sources")
+			os.Exit(ExitUsageError)
+		}
+		source.Path = absPath
+	}
+
+	if source.Name == "" {
+		source.Name = sourceNameFromLocation(location)
+	}
+	if source.Name == "" || strings.ContainsAny(source.Name, `/\`) {
+		ui.Error("Invalid source name %q; use --name to choose one", source.Name)
+		os.Exit(ExitUsageError)
+	}
+
+	cm, config := loadSourceConfig()
+	for _, existing := range config.Sources {
+		if existing.Name == source.Name {
+			ui.Error("A source named '%s' already exists; use --name to choose another name", source.Name)
+			os.Exit(ExitConfigError)
+		}
+	}
+
+	config.Sources = append(config.Sources, source)
+	saveSourceConfig(cm, config)
+
+	utils.Info("Rule source added | name=" + source.Name + ", location=" + source.Location())
+	ui.Success("Added rule source '%s' (priority %d)", source.Name, source.Priority)
+	ui.Plain("Run %s or %s to install its agents", ui.SuccessStyle.Sprint("cursor++ init"), ui.SuccessStyle.Sprint("cursor++ update"))
+}
+
+func handleSourceRemove(args []string) {
+	fs := newCommandFlags("source remove", printSourceUsage)
+	positional := parseCommandFlags(fs, args)
+
+	if len(positional) != 1 {
+		ui.Error("source remove requires exactly one source name")
+		printSourceUsage()
+		os.Exit(ExitUsageError)
+	}
+	name := positional[0]
+
+	cm, config := loadSourceConfig()
+	remaining := make([]utils.RuleSource, 0, len(config.Sources))
+	for _, source := range config.Sources {
+		if source.Name != name {
+			remaining = append(remaining, source)
+		}
+	}
+	if len(remaining) == len(config.Sources) {
+		ui.Error("No rule source named '%s'", name)
+		os.Exit(ExitConfigError)
+	}
+	if len(remaining) == 0 {
+		ui.Error("Cannot remove the last rule source")
+		os.Exit(ExitConfigError)
+	}
+
+	config.Sources = remaining
+	saveSourceConfig(cm, config)
+
+	utils.Info("Rule source removed | name=" + name)
+	ui.Success("Removed rule source '%s'", name)
+}
+
+// isRemoteLocation reports whether a source location should be cloned with git
+func isRemoteLocation(location string) bool {
+	return strings.Contains(location, "://") || strings.HasPrefix(location, "git@")
+}
+
+// sourceNameFromLocation derives a source name from the last element of a URL or path
+func sourceNameFromLocation(location string) string {
+	location = strings.TrimRight(location, "/")
+	if i := strings.LastIndexAny(location, "/:"); i >= 0 {
+		location = location[i+1:]
+	}
+	return strings.TrimSuffix(location, ".git")
+}
+
+func printSourceUsage() {
+	ui.Header("Usage: cursor++ source <subcommand> [OPTIONS]")
+
+	ui.Plain("\nSubcommands:")
+	ui.Plain("  list                  List the configured rule sources (default)")
+	ui.Plain("  add <url|path>        Add a git repository or local directory as a rule source")
+	ui.Plain("  remove <name>         Remove a rule source")
+
+	ui.Plain("\nOptions for add:")
+	ui.Plain("  --name <name>         Name of the source (defaults to the repository or directory name)")
+	ui.Plain("  --ref <ref>           Branch, tag or commit to check out")
+	ui.Plain("  --subfolder <dir>     Folder inside the source holding the rules")
+	ui.Plain("  --priority <n>        Higher priorities win when agent IDs clash (default 0)")
+
+	ui.Plain("\nExample usage:")
+	ui.Plain("  cursor++ source add https://github.com/acme/agents.git --priority 10")
+	ui.Plain("  cursor++ source add ./team-rules --name team --priority 20")
+	ui.Plain("  cursor++ source remove team")
+}
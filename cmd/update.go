@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"cursor++/internal/core"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+func handleUpdate(manager *core.AgentInitializer) {
+	utils.Debug("Handling update command")
+
+	// Allow the user to interrupt a long running update between projects
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interruptCh := make(chan os.Signal, 1)
+	signal.Notify(interruptCh, os.Interrupt)
+	go func() {
+		<-interruptCh
+		utils.Info("User interrupted update")
+		cancel()
+	}()
+
+	fmt.Println()
+
+	results, err := manager.Update(ctx)
+	if err != nil && len(results) == 0 {
+		handleCommandError("Update", err, ExitUpdateError)
+	}
+
+	fmt.Println()
+
+	if len(results) == 0 {
+		ui.Warning("No projects are registered with cursor++")
+		ui.Plain("Run %s in a project to register it", ui.SuccessStyle.Sprint("cursor++ init"))
+		return
+	}
+
+	rows := make([]ui.ProjectSummary, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		if result.Status == core.UpdateStatusFailed {
+			failed++
+		}
+		rows = append(rows, ui.ProjectSummary{
+			Project: result.Project,
+			Status:  string(result.Status),
+			Details: result.Detail,
+		})
+	}
+
+	ui.Header("Update Summary:")
+	ui.DisplayProjectSummary(rows)
+	fmt.Println()
+
+	// A canceled update still reports the projects it got through
+	if err != nil {
+		handleCommandError("Update", err, ExitUpdateError)
+	}
+
+	if failed > 0 {
+		ui.Error("%d project(s) failed to update", failed)
+		os.Exit(ExitUpdateError)
+	}
+
+	utils.Info("Update command completed successfully")
+}
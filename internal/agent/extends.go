@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ExtendsKey is the frontmatter key naming the agent a definition inherits from
+const ExtendsKey = "extends"
+
+// appendMarker is the heading suffix that appends a section to the parent's section instead of replacing it
+const appendMarker = "(append)"
+
+// Inheritance errors, wrapped with the agents involved
+var (
+	ErrExtendsCycle   = errors.New("inheritance cycle")
+	ErrParentNotFound = errors.New("parent agent not found")
+)
+
+// AgentLookup returns the raw content of an agent file by ID; found is false if there is no such agent
+type AgentLookup func(id string) (content string, found bool, err error)
+
+// section is a "## " section of an agent body. The preamble before the first section has no heading.
+type section struct {
+	heading string
+	lines   []string
+}
+
+// ExtendsOf returns the ID of the agent a definition extends, or an empty string if it extends none
+func ExtendsOf(content string) (string, error) {
+	block, _, ok := SplitFrontmatter(content)
+	if !ok {
+		return "", nil
+	}
+	fm, err := ParseFrontmatter(block)
+	if err != nil {
+		return "", err
+	}
+	return fm.Get(ExtendsKey), nil
+}
+
+// ResolveExtends returns the fully rendered content of an agent, applying the chain of
+// agents it extends from the root down. Agents that extend nothing are returned unchanged.
+func ResolveExtends(id string, lookup AgentLookup) (string, error) {
+	content, found, err := lookup(id)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("agent not found: %s", id)
+	}
+	return resolveExtends(id, content, lookup, nil)
+}
+
+func resolveExtends(id, content string, lookup AgentLookup, chain []string) (string, error) {
+	for i, seen := range chain {
+		if seen == id {
+			return "", fmt.Errorf("%w: %s", ErrExtendsCycle, strings.Join(append(chain[i:], id), " -> "))
+		}
+	}
+
+	parentID, err := ExtendsOf(content)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", id, err)
+	}
+	if parentID == "" {
+		return content, nil
+	}
+	if !validateAgentID(parentID) {
+		return "", fmt.Errorf("%s: invalid agent ID in %s: %q", id, ExtendsKey, parentID)
+	}
+
+	parentContent, found, err := lookup(parentID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s extends %s", ErrParentNotFound, id, parentID)
+	}
+
+	parent, err := resolveExtends(parentID, parentContent, lookup, append(chain, id))
+	if err != nil {
+		return "", err
+	}
+	return ExtendAgent(parentID, parent, content)
+}
+
+// ExtendAgent applies a child definition to the rendered content of its parent:
+//   - frontmatter keys of the child replace the parent's, and the extends key is dropped
+//   - a "# " title in the child replaces the parent's title, and other text before the
+//     first section replaces the parent's text there
+//   - a "## " section replaces the parent's section with the same heading; headings are
+//     matched ignoring emoji, case and a trailing colon, so "## Role" matches "## 🎯 Role:"
+//   - a heading ending in "(append)" adds its text to the end of the parent's section
+//   - sections the parent does not have are added at the end
+func ExtendAgent(parentID, parent, child string) (string, error) {
+	parentBlock, parentBody, _ := SplitFrontmatter(parent)
+	childBlock, childBody, _ := SplitFrontmatter(child)
+
+	parentFM, err := ParseFrontmatter(parentBlock)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", parentID, err)
+	}
+	childFM, err := ParseFrontmatter(childBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return JoinFrontmatter(extendFrontmatter(parentID, parentFM, childFM).String(), extendBody(parentBody, childBody)), nil
+}
+
+// extendFrontmatter overlays the keys of a child frontmatter onto its parent's
+func extendFrontmatter(parentID string, parent, child *Frontmatter) *Frontmatter {
+	merged := &Frontmatter{Entries: []FrontmatterEntry{{
+		Lines: []string{"# Rendered by cursor++: this agent extends " + parentID},
+	}}}
+
+	for _, entry := range parent.Entries {
+		if entry.Key == "" || entry.Key == ExtendsKey {
+			continue
+		}
+		if override, ok := child.entry(entry.Key); ok {
+			entry = override
+		}
+		merged.Entries = append(merged.Entries, entry)
+	}
+	for _, entry := range child.Entries {
+		if entry.Key == "" || entry.Key == ExtendsKey || parent.Has(entry.Key) {
+			continue
+		}
+		merged.Entries = append(merged.Entries, entry)
+	}
+	return merged
+}
+
+// extendBody applies the title, preamble and sections of a child body to its parent's
+func extendBody(parent, child string) string {
+	parentSections := splitSections(parent)
+	childSections := splitSections(child)
+
+	parentSections[0].lines = extendPreamble(parentSections[0].lines, childSections[0].lines)
+
+	for _, cs := range childSections[1:] {
+		name, appendText := sectionName(cs.heading)
+
+		index := -1
+		for i, ps := range parentSections[1:] {
+			if parentName, _ := sectionName(ps.heading); parentName == name {
+				index = i + 1
+				break
+			}
+		}
+
+		if index == -1 {
+			heading := cs.heading
+			if appendText {
+				heading = strings.TrimRight(strings.TrimSuffix(strings.TrimRight(heading, " \t"), appendMarker), " \t")
+			}
+			last := &parentSections[len(parentSections)-1]
+			if len(last.lines) > 0 && strings.TrimSpace(last.lines[len(last.lines)-1]) != "" {
+				last.lines = append(last.lines, "")
+			}
+			parentSections = append(parentSections, section{heading: heading, lines: cs.lines})
+			continue
+		}
+
+		// The parent's heading, spacing and trailing separator are kept
+		target := &parentSections[index]
+		content, tail := splitTail(target.lines)
+		added, _ := splitTail(trimLeadingBlank(cs.lines))
+		switch {
+		case appendText:
+			content = append(append(content, ""), added...)
+		case len(target.lines) > 0 && strings.TrimSpace(target.lines[0]) == "":
+			content = append([]string{""}, added...)
+		default:
+			content = added
+		}
+		target.lines = append(content, tail...)
+	}
+
+	var lines []string
+	for _, s := range parentSections {
+		if s.heading != "" {
+			lines = append(lines, s.heading)
+		}
+		lines = append(lines, s.lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// extendPreamble replaces the title and text before the first section with the child's, where given
+func extendPreamble(parent, child []string) []string {
+	parentTitle, parentText := splitTitle(parent)
+	childTitle, childText := splitTitle(child)
+
+	title := parentTitle
+	if childTitle != "" {
+		title = childTitle
+	}
+	text := parentText
+	if strings.TrimSpace(strings.Join(childText, "")) != "" {
+		text = childText
+	}
+
+	if title == "" {
+		return text
+	}
+	return append([]string{title}, text...)
+}
+
+// splitTitle separates the "# " title line from the other lines of a preamble
+func splitTitle(lines []string) (string, []string) {
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "# ") {
+			rest := append(append([]string{}, lines[:i]...), lines[i+1:]...)
+			return line, rest
+		}
+	}
+	return "", lines
+}
+
+// splitSections splits a body at its "## " headings, ignoring headings inside code fences.
+// The first section is the preamble and has no heading.
+func splitSections(body string) []section {
+	sections := []section{{}}
+	var fence CodeFence
+	for _, line := range strings.Split(body, "\n") {
+		if !fence.Contains(line) && strings.HasPrefix(strings.TrimSpace(line), "## ") {
+			sections = append(sections, section{heading: line})
+			continue
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, line)
+	}
+	return sections
+}
+
+// sectionName normalizes a "## " heading for matching and reports whether it carries the append marker
+func sectionName(heading string) (string, bool) {
+	name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(heading), "## "))
+	appendText := false
+	if strings.HasSuffix(strings.ToLower(name), appendMarker) {
+		appendText = true
+		name = strings.TrimSpace(name[:len(name)-len(appendMarker)])
+	}
+	name = strings.TrimSuffix(name, ":")
+	name = strings.TrimLeftFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ToLower(strings.TrimSpace(name)), appendText
+}
+
+// splitTail separates the trailing blank lines and "---" separators of a section from its content
+func splitTail(lines []string) ([]string, []string) {
+	end := len(lines)
+	for end > 0 {
+		trimmed := strings.TrimSpace(lines[end-1])
+		if trimmed != "" && trimmed != frontmatterDelimiter {
+			break
+		}
+		end--
+	}
+	return append([]string{}, lines[:end]...), append([]string{}, lines[end:]...)
+}
+
+// trimLeadingBlank drops the blank lines at the start of lines
+func trimLeadingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	return lines
+}
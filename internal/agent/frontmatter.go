@@ -0,0 +1,353 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// frontmatterDelimiter opens and closes the frontmatter block of an MDC file
+const frontmatterDelimiter = "---"
+
+// Attach modes, named after Cursor's rule types
+const (
+	AttachAlways         = "Always"
+	AttachAuto           = "Auto Attached"
+	AttachAgentRequested = "Agent Requested"
+	AttachManual         = "Manual"
+)
+
+// FrontmatterEntry is a single key of a frontmatter block together with its raw lines,
+// so the block can be written back without reformatting it
+type FrontmatterEntry struct {
+	Key   string   // Empty for comments and blank lines before the first key
+	Lines []string // Key line followed by continuation, comment and blank lines
+}
+
+// Frontmatter is the metadata block between --- lines at the top of an MDC file.
+// Only the subset of YAML used by Cursor rules is understood: scalar keys,
+// flow lists, block lists and folded or literal block scalars.
+type Frontmatter struct {
+	Entries []FrontmatterEntry
+}
+
+// SplitFrontmatter separates the frontmatter block of an MDC file from its body.
+// The returned block excludes the delimiter lines; ok is false if the file has no frontmatter.
+func SplitFrontmatter(content string) (block string, body string, ok bool) {
+	normalized := strings.TrimPrefix(content, "\ufeff")
+	first, rest, found := strings.Cut(normalized, "\n")
+	if !found || strings.TrimRight(first, " \r") != frontmatterDelimiter {
+		return "", content, false
+	}
+
+	offset := 0
+	for offset <= len(rest) {
+		line, _, more := strings.Cut(rest[offset:], "\n")
+		if strings.TrimRight(line, " \r") == frontmatterDelimiter {
+			end := offset + len(line)
+			if more {
+				end++
+			}
+			return rest[:offset], rest[end:], true
+		}
+		if !more {
+			break
+		}
+		offset += len(line) + 1
+	}
+
+	return "", content, false
+}
+
+// JoinFrontmatter puts a frontmatter block and a body back together
+func JoinFrontmatter(block, body string) string {
+	if block != "" && !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+	return frontmatterDelimiter + "\n" + block + frontmatterDelimiter + "\n" + body
+}
+
+// CodeFence follows the fenced code blocks of Markdown text line by line. As in CommonMark, a
+// block opened by a run of backticks or tildes only ends at a line holding nothing but a run of
+// the same character at least as long, so fences with an info string nested in it stay inside.
+// Rules often show an indented example block inside another one, so a closing fence must also
+// not be indented deeper than the opening one.
+type CodeFence struct {
+	marker string // Run that opened the current block, empty outside blocks
+	indent int    // Indentation of the opening fence
+}
+
+// Contains reports whether a line belongs to a code block, its fence lines included
+func (f *CodeFence) Contains(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	if f.marker == "" {
+		for _, char := range []string{"`", "~"} {
+			if strings.HasPrefix(trimmed, char+char+char) {
+				f.marker = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, char))]
+				f.indent = indent
+				return true
+			}
+		}
+		return false
+	}
+	if indent <= f.indent && strings.HasPrefix(trimmed, f.marker) && strings.Trim(trimmed, f.marker[:1]) == "" {
+		f.marker = ""
+	}
+	return true
+}
+
+// Closing returns the fence line that closes the code block the last line left open,
+// or an empty string if it is outside code blocks
+func (f *CodeFence) Closing() string {
+	if f.marker == "" {
+		return ""
+	}
+	return strings.Repeat(" ", f.indent) + f.marker
+}
+
+// ParseFrontmatter parses a frontmatter block as returned by SplitFrontmatter
+func ParseFrontmatter(block string) (*Frontmatter, error) {
+	fm := &Frontmatter{}
+	lines := strings.Split(strings.TrimSuffix(block, "\n"), "\n")
+	if block == "" {
+		return fm, nil
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		continuation := trimmed == "" || strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(trimmed, "- ") || trimmed == "-"
+
+		if continuation {
+			if len(fm.Entries) == 0 {
+				if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+					return nil, fmt.Errorf("line %d: value without a key", i+2)
+				}
+				fm.Entries = append(fm.Entries, FrontmatterEntry{})
+			}
+			last := &fm.Entries[len(fm.Entries)-1]
+			last.Lines = append(last.Lines, line)
+			continue
+		}
+
+		key, _, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", i+2)
+		}
+		if _, exists := fm.entry(key); exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q", i+2, key)
+		}
+		fm.Entries = append(fm.Entries, FrontmatterEntry{Key: key, Lines: []string{line}})
+	}
+
+	return fm, nil
+}
+
+// String returns the raw frontmatter block
+func (f *Frontmatter) String() string {
+	var lines []string
+	for _, entry := range f.Entries {
+		lines = append(lines, entry.Lines...)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Keys returns the keys in the order they appear
+func (f *Frontmatter) Keys() []string {
+	keys := make([]string, 0, len(f.Entries))
+	for _, entry := range f.Entries {
+		if entry.Key != "" {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// Raw returns the raw lines of a key, including its key line
+func (f *Frontmatter) Raw(key string) (string, bool) {
+	entry, ok := f.entry(key)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(entry.Lines, "\n"), true
+}
+
+// Has reports whether a key is present
+func (f *Frontmatter) Has(key string) bool {
+	_, ok := f.entry(key)
+	return ok
+}
+
+// Get returns the scalar value of a key, unquoted.
+// Folded (>) and literal (|) block scalars are joined with spaces and newlines respectively.
+func (f *Frontmatter) Get(key string) string {
+	entry, ok := f.entry(key)
+	if !ok {
+		return ""
+	}
+
+	value := entryValue(entry)
+	continuation := continuationValues(entry)
+
+	switch {
+	case strings.HasPrefix(value, "|"):
+		return strings.Join(continuation, "\n")
+	case strings.HasPrefix(value, ">"), value == "" && len(continuation) > 0:
+		return strings.Join(continuation, " ")
+	default:
+		return unquote(value)
+	}
+}
+
+// List returns the values of a key holding a list. Besides YAML flow and block lists,
+// the comma-separated strings Cursor writes for globs are accepted.
+func (f *Frontmatter) List(key string) []string {
+	entry, ok := f.entry(key)
+	if !ok {
+		return nil
+	}
+
+	var items []string
+	value := entryValue(entry)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	}
+	for _, item := range SplitList(value) {
+		if item = unquote(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	for _, line := range continuationValues(entry) {
+		if !strings.HasPrefix(line, "-") {
+			continue
+		}
+		if item := unquote(strings.TrimSpace(strings.TrimPrefix(line, "-"))); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Bool returns the boolean value of a key; missing and empty keys are false
+func (f *Frontmatter) Bool(key string) (bool, error) {
+	value := f.Get(key)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(strings.ToLower(value))
+	if err != nil {
+		return false, fmt.Errorf("%s: %q is not a boolean", key, value)
+	}
+	return b, nil
+}
+
+func (f *Frontmatter) entry(key string) (FrontmatterEntry, bool) {
+	for _, entry := range f.Entries {
+		if entry.Key == key {
+			return entry, true
+		}
+	}
+	return FrontmatterEntry{}, false
+}
+
+// entryValue returns the text after the colon of an entry's key line
+func entryValue(entry FrontmatterEntry) string {
+	_, value, _ := strings.Cut(entry.Lines[0], ":")
+	return strings.TrimSpace(stripComment(value))
+}
+
+// continuationValues returns the trimmed, non-empty continuation lines of an entry, skipping comments
+func continuationValues(entry FrontmatterEntry) []string {
+	var values []string
+	for _, line := range entry.Lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		values = append(values, trimmed)
+	}
+	return values
+}
+
+// FrontmatterValue formats a scalar for a frontmatter line, quoting it if it would
+// otherwise be read back differently
+func FrontmatterValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	if strings.ContainsAny(value[:1], "\"'[{>|&*!%@`#-") || strings.Contains(value, ": ") ||
+		strings.Contains(value, " #") || strings.TrimSpace(value) != value || strings.Contains(value, "\n") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// SplitList splits a comma-separated list, keeping brace alternatives such as
+// **/*.{ts,tsx} in one piece. Items are trimmed and empty items dropped.
+func SplitList(value string) []string {
+	var items []string
+	add := func(item string) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				add(value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(value[start:])
+	return items
+}
+
+// stripComment removes a trailing " # comment" outside of quotes
+func stripComment(value string) string {
+	var quote rune
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// unquote removes matching single or double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			if first == '"' {
+				if unquoted, err := strconv.Unquote(value); err == nil {
+					return unquoted
+				}
+			}
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// agentMention matches @id.mdc, the way Cursor references a rule; the surrounding characters are checked separately
+var agentMention = regexp.MustCompile(`@([A-Za-z0-9][A-Za-z0-9_\-]*)\.mdc`)
+
+// inlineCode matches `code spans`, which hold examples and placeholders rather than references
+var inlineCode = regexp.MustCompile("`[^`]*`")
+
+// AgentReference is a mention of another agent in an agent file, such as "use @implementer.mdc to invoke"
+type AgentReference struct {
+	Target string // Referenced agent ID
+	Line   int    // Line number in the content, starting at 1
+}
+
+// FindReferences returns the agents mentioned in content as @id.mdc. Bare @words such as
+// @param or @Override in prose are not references. Mentions in code blocks and code spans
+// are ignored, as are e-mail addresses and paths such as docs/@old.mdc.
+func FindReferences(content string) []AgentReference {
+	var references []AgentReference
+	var fence CodeFence
+
+	for i, line := range strings.Split(content, "\n") {
+		if fence.Contains(line) {
+			continue
+		}
+
+		line = inlineCode.ReplaceAllStringFunc(line, func(code string) string {
+			return strings.Repeat(" ", len(code))
+		})
+		for _, match := range agentMention.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[0], match[1]
+			if start > 0 && isMentionChar(line[start-1]) {
+				continue
+			}
+			if end < len(line) && (line[end] == '/' || isMentionChar(line[end])) {
+				continue
+			}
+			references = append(references, AgentReference{Target: line[match[2]:match[3]], Line: i + 1})
+		}
+	}
+	return references
+}
+
+// isMentionChar reports whether c may not directly surround an agent mention
+func isMentionChar(c byte) bool {
+	return c == '@' || c == '/' || c == '_' || c == '-' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
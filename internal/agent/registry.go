@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -103,51 +104,171 @@ func (r *Registry) scanAgents() error {
 		}
 	}
 
+	r.resolveInheritance()
+
 	r.reportProgress("scan_complete", fmt.Sprintf("%d agents loaded", len(r.agents)))
 	return nil
 }
 
-// processAgentFile parses an agent definition file and adds it to the registry
-func (r *Registry) processAgentFile(path string) error {
+// resolveInheritance renders every agent that extends another one, so its metadata and
+// content describe the complete agent. Agents whose parent is missing or that are part
+// of an inheritance cycle are dropped from the registry.
+func (r *Registry) resolveInheritance() {
+	ids := make([]string, 0)
+	for id, definition := range r.agents {
+		if definition.Extends != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	rendered := make(map[string]string, len(ids))
+	for _, id := range ids {
+		content, err := r.Render(id)
+		if err != nil {
+			utils.Warn("Failed to resolve agent inheritance | id=" + id + ", error=" + err.Error())
+			r.reportProgress("process_error", id+": "+err.Error())
+			continue
+		}
+		rendered[id] = content
+	}
+
+	// Apply the results only now, as Render reads the unresolved definitions
+	for _, id := range ids {
+		content, ok := rendered[id]
+		if !ok {
+			delete(r.agents, id)
+			continue
+		}
+		applyRenderedContent(r.agents[id], content)
+	}
+}
+
+// Render returns the complete content of an agent, with the agents it extends applied
+func (r *Registry) Render(id string) (string, error) {
+	return ResolveExtends(id, r.readAgentFile)
+}
+
+// readAgentFile is an AgentLookup returning the file content of an agent as written
+func (r *Registry) readAgentFile(id string) (string, bool, error) {
+	definition, exists := r.agents[id]
+	if !exists || definition.DefinitionPath == "" {
+		return "", false, nil
+	}
+	content, err := os.ReadFile(definition.DefinitionPath)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read agent file: %w", err)
+	}
+	return string(content), true, nil
+}
+
+// applyRenderedContent replaces the metadata of an extending agent with that of its rendered content
+func applyRenderedContent(definition *AgentDefinition, content string) {
+	block, body, _ := SplitFrontmatter(content)
+	definition.Name, definition.Description = extractAgentMetadata(body, definition.ID)
+	definition.Tokens = EstimateTokens(body)
+
+	// The rendered frontmatter no longer names the parent
+	extends := definition.Extends
+	if err := applyFrontmatter(definition, block); err != nil {
+		utils.Warn("Invalid frontmatter | id=" + definition.ID + ", error=" + err.Error())
+	}
+	definition.Extends = extends
+	definition.Content = content
+}
+
+// LoadDefinition parses a single agent definition file without adding it to a registry
+func LoadDefinition(path string) (*AgentDefinition, error) {
 	// Extract agent ID from path
 	id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 
 	// Validate agent ID
 	if !validateAgentID(id) {
 		utils.Warn("Invalid agent ID | id=" + id + ", path=" + path)
-		return fmt.Errorf("invalid agent ID: %s", id)
+		return nil, fmt.Errorf("invalid agent ID: %s", id)
+	}
+
+	definition := &AgentDefinition{
+		ID:             id,
+		Version:        "1.0", // Default version
+		Type:           "ai",  // Default type
+		Config:         make(map[string]interface{}),
+		LastUpdated:    time.Now(),
+		DefinitionPath: path,
 	}
 
 	// Get agent metadata from file
-	name, description, err := r.extractAgentMetadata(path)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		utils.Warn("Failed to extract agent metadata | id=" + id + ", path=" + path + ", error=" + err.Error())
-		name = id // Use ID as name if metadata extraction fails
-		description = "No description available"
+		utils.Warn("Failed to read agent file | id=" + id + ", path=" + path + ", error=" + err.Error())
+		definition.Name = id // Use ID as name if metadata extraction fails
+		definition.Description = "No description available"
+		return definition, nil
 	}
 
-	// Check for templates
-	templates, err := r.findTemplates(id)
+	block, body, hasFrontmatter := SplitFrontmatter(string(content))
+	definition.Name, definition.Description = extractAgentMetadata(body, id)
+	definition.Tokens = EstimateTokens(body)
+
+	if hasFrontmatter {
+		if err := applyFrontmatter(definition, block); err != nil {
+			utils.Warn("Invalid frontmatter | id=" + id + ", path=" + path + ", error=" + err.Error())
+		}
+	}
+
+	return definition, nil
+}
+
+// applyFrontmatter copies the Cursor rule settings and the declared metadata
+// from a frontmatter block into a definition
+func applyFrontmatter(definition *AgentDefinition, block string) error {
+	fm, err := ParseFrontmatter(block)
 	if err != nil {
-		utils.Warn("Failed to find templates | id=" + id + ", error=" + err.Error())
-		// Continue without templates
+		return err
 	}
+	definition.HasFrontmatter = true
 
-	// Create agent definition
-	agent := &AgentDefinition{
-		ID:          id,
-		Name:        name,
-		Description: description,
-		Version:     "1.0", // Default version
-		Type:        "ai",  // Default type
-		Config:      make(map[string]interface{}),
-		Templates:   templates,
-		LastUpdated: time.Now(),
+	definition.FrontmatterDescription = fm.Get("description")
+	if definition.FrontmatterDescription != "" {
+		definition.Description = definition.FrontmatterDescription
 	}
+	definition.Globs = fm.List("globs")
+
+	if version := fm.Get("version"); version != "" {
+		definition.Version = version
+	}
+	definition.Author = fm.Get("author")
+	definition.Tags = fm.List("tags")
+	definition.Category = fm.Get("category")
+	definition.Icon = fm.Get("icon")
+	definition.Extends = fm.Get(ExtendsKey)
+
+	alwaysApply, err := fm.Bool("alwaysApply")
+	if err != nil {
+		return err
+	}
+	definition.AlwaysApply = alwaysApply
+	return nil
+}
+
+// processAgentFile parses an agent definition file and adds it to the registry
+func (r *Registry) processAgentFile(path string) error {
+	agent, err := LoadDefinition(path)
+	if err != nil {
+		return err
+	}
+
+	// Check for templates
+	templates, err := r.findTemplates(agent.ID)
+	if err != nil {
+		utils.Warn("Failed to find templates | id=" + agent.ID + ", error=" + err.Error())
+		// Continue without templates
+	}
+	agent.Templates = templates
 
 	// Add to registry
-	r.agents[id] = agent
-	utils.Debug("Added agent to registry | id=" + id + ", name=" + name)
+	r.agents[agent.ID] = agent
+	utils.Debug("Added agent to registry | id=" + agent.ID + ", name=" + agent.Name)
 
 	return nil
 }
@@ -215,18 +336,12 @@ func (r *Registry) ScanAgentsWithAnimation() error {
 	return r.scanAgents()
 }
 
-// extractAgentMetadata extracts name and description from an agent file
-func (r *Registry) extractAgentMetadata(path string) (string, string, error) {
-	// Read file
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to read file: %v", err)
-	}
-
+// extractAgentMetadata extracts name and description from the body of an agent file
+func extractAgentMetadata(body string, id string) (string, string) {
 	// Extract metadata from the file
 	// For simplicity, we're assuming the first line after "# " is the agent name
 	// and the content after "## 🎯 Role:" is the description
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(body, "\n")
 	var name, description string
 
 	for i, line := range lines {
@@ -249,11 +364,10 @@ func (r *Registry) extractAgentMetadata(path string) (string, string, error) {
 
 	// If we couldn't extract a name, use the filename
 	if name == "" {
-		base := filepath.Base(path)
-		name = strings.TrimSuffix(base, ".mdc")
+		name = id
 	}
 
-	return name, description, nil
+	return name, description
 }
 
 // findTemplates looks for template files associated with an agent
@@ -281,6 +395,42 @@ func (r *Registry) findTemplates(agentID string) ([]string, error) {
 	return templates, nil
 }
 
+// TemplatePath returns the path of one of an agent's templates. name may be the part of the
+// file name after "<id>-" without the .tmpl extension, or the full file name; it may be empty
+// if the agent has exactly one template.
+func (r *Registry) TemplatePath(id, name string) (string, error) {
+	definition, err := r.GetAgent(id)
+	if err != nil {
+		return "", err
+	}
+	if len(definition.Templates) == 0 {
+		return "", fmt.Errorf("agent %s has no templates (expected templates/%s-<name>.tmpl)", id, id)
+	}
+
+	if name == "" {
+		if len(definition.Templates) > 1 {
+			return "", fmt.Errorf("agent %s has several templates, choose one of: %s", id, strings.Join(TemplateNames(definition), ", "))
+		}
+		return filepath.Join(r.rulesDir, "templates", definition.Templates[0]), nil
+	}
+
+	for _, file := range definition.Templates {
+		if file == name || file == id+"-"+name+".tmpl" {
+			return filepath.Join(r.rulesDir, "templates", file), nil
+		}
+	}
+	return "", fmt.Errorf("agent %s has no template %q, choose one of: %s", id, name, strings.Join(TemplateNames(definition), ", "))
+}
+
+// TemplateNames returns the short names of an agent's templates, as accepted by TemplatePath
+func TemplateNames(definition *AgentDefinition) []string {
+	names := make([]string, 0, len(definition.Templates))
+	for _, file := range definition.Templates {
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(file, definition.ID+"-"), ".tmpl"))
+	}
+	return names
+}
+
 // GetRulesDir returns the rules directory path
 func (r *Registry) GetRulesDir() string {
 	return r.rulesDir
@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scaffold describes a new agent definition to generate
+type Scaffold struct {
+	ID               string
+	Name             string
+	Icon             string
+	Description      string   // One sentence summary, used as the frontmatter description
+	Role             string   // Paragraph for the Role section
+	Reminders        []string // Bullets for the Important Reminders block
+	Responsibilities []string // Headings for the Core Responsibilities section
+	Handoffs         []string // IDs of the agents to recommend next
+	Category         string
+	Tags             []string
+	Globs            []string
+	AlwaysApply      bool
+	Author           string
+	Version          string
+}
+
+// IsValidAgentID reports whether id may be used as an agent ID
+func IsValidAgentID(id string) bool {
+	return validateAgentID(id)
+}
+
+// DefaultAgentName derives a display name from an agent ID, e.g. "release-manager" becomes "Release Manager Agent"
+func DefaultAgentName(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '-' || r == '_' })
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(append(words, "Agent"), " ")
+}
+
+// Validate checks that the scaffold produces a well-formed agent definition
+func (s *Scaffold) Validate() error {
+	if !validateAgentID(s.ID) {
+		return fmt.Errorf("invalid agent ID: %q (IDs may not contain dots, slashes or spaces)", s.ID)
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("agent name is required")
+	}
+	if strings.TrimSpace(s.Description) == "" {
+		return fmt.Errorf("agent description is required")
+	}
+	for _, id := range s.Handoffs {
+		if !validateAgentID(id) {
+			return fmt.Errorf("invalid handoff agent ID: %q", id)
+		}
+	}
+	return nil
+}
+
+// Render returns the content of the agent file, laid out like the bundled agents
+func (s *Scaffold) Render() string {
+	var b strings.Builder
+
+	b.WriteString(frontmatterDelimiter + "\n")
+	b.WriteString("description: " + FrontmatterValue(s.Description) + "\n")
+	b.WriteString("globs: " + strings.Join(s.Globs, ", ") + "\n")
+	b.WriteString("alwaysApply: " + strconv.FormatBool(s.AlwaysApply) + "\n")
+	if s.Version != "" {
+		b.WriteString("version: " + FrontmatterValue(s.Version) + "\n")
+	}
+	if s.Author != "" {
+		b.WriteString("author: " + FrontmatterValue(s.Author) + "\n")
+	}
+	if len(s.Tags) > 0 {
+		b.WriteString("tags: [" + strings.Join(s.Tags, ", ") + "]\n")
+	}
+	if s.Category != "" {
+		b.WriteString("category: " + FrontmatterValue(s.Category) + "\n")
+	}
+	if s.Icon != "" {
+		b.WriteString("icon: " + FrontmatterValue(s.Icon) + "\n")
+	}
+	b.WriteString(frontmatterDelimiter + "\n")
+
+	title := s.Name
+	if s.Icon != "" {
+		title = s.Icon + " " + title
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	b.WriteString("## 🎯 Role:\n")
+	b.WriteString(orPlaceholder(s.Role, "You are the **"+s.Name+"**. "+strings.TrimSuffix(s.Description, ".")+".") + "\n\n")
+
+	b.WriteString("> ⚠️ **Important Reminders:**\n")
+	reminders := s.Reminders
+	if len(reminders) == 0 {
+		reminders = []string{"TODO: state what this agent must always or never do."}
+	}
+	for _, reminder := range reminders {
+		b.WriteString("> - " + reminder + "\n")
+	}
+	b.WriteString("\n---\n\n")
+
+	b.WriteString("## 🛠️ Core Responsibilities:\n\n")
+	responsibilities := s.Responsibilities
+	if len(responsibilities) == 0 {
+		responsibilities = []string{"TODO: name a responsibility"}
+	}
+	for _, responsibility := range responsibilities {
+		fmt.Fprintf(&b, "### ✅ %s:\n", strings.TrimSuffix(responsibility, ":"))
+		b.WriteString("- TODO: describe what the agent does for this responsibility.\n\n")
+	}
+	b.WriteString("---\n\n")
+
+	b.WriteString("## 🔄 Agent System Integration:\n\n")
+	b.WriteString("- You are part of a **multi-agent system** working together to assist users with software development.\n")
+	fmt.Fprintf(&b, "- Your focus is exclusively on the work of the **%s**.\n", s.Name)
+	for _, id := range s.Handoffs {
+		fmt.Fprintf(&b, "- Hand off to **@%s** when its expertise is needed.\n", id)
+	}
+	b.WriteString("\n---\n\n")
+
+	b.WriteString("## 🔄 Next Agent Recommendation:\n\n")
+	b.WriteString("Always conclude your responses with a specific recommendation for which agent the user should invoke next. Format your recommendation as follows:\n\n")
+	b.WriteString("\"The [Agent Name] would be best for [specific next step]. [1-2 sentence explanation why this agent is most appropriate].\n\n")
+	b.WriteString("use @[agent-filename].mdc to invoke\"\n")
+
+	if len(s.Handoffs) > 0 {
+		b.WriteString("\n### Example Recommendations:\n")
+		for _, id := range s.Handoffs {
+			fmt.Fprintf(&b, "\n\"The %s would be best for [specific next step]. [Why this agent is most appropriate].\n\n", DefaultAgentName(id))
+			fmt.Fprintf(&b, "use @%s.mdc to invoke\"\n", id)
+		}
+	}
+
+	return b.String()
+}
+
+// orPlaceholder returns value, or a TODO placeholder based on fallback if value is empty
+func orPlaceholder(value, fallback string) string {
+	if strings.TrimSpace(value) != "" {
+		return value
+	}
+	return fallback + " TODO: expand on the role."
+}
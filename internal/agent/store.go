@@ -0,0 +1,279 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor++/internal/utils"
+)
+
+// SettingContent is the AgentConfig setting holding the agent file content for AddAgent
+const SettingContent = "content"
+
+// FileRegistry implements AgentRegistry on top of a Registry.
+// Agents are stored as .mdc files in the registry's rules directory.
+type FileRegistry struct {
+	registry *Registry
+}
+
+var _ AgentRegistry = (*FileRegistry)(nil)
+
+// NewFileRegistry creates an AgentRegistry backed by the rules directory of registry
+func NewFileRegistry(registry *Registry) *FileRegistry {
+	return &FileRegistry{registry: registry}
+}
+
+// LoadAgents rescans the rules directory
+func (f *FileRegistry) LoadAgents() error {
+	f.registry.agents = make(map[string]*AgentDefinition)
+	return f.registry.scanAgents()
+}
+
+// GetAgent returns the configuration of an agent by ID
+func (f *FileRegistry) GetAgent(id string) (*AgentConfig, error) {
+	definition, err := f.registry.GetAgent(id)
+	if err != nil {
+		return nil, err
+	}
+	return definitionToConfig(definition), nil
+}
+
+// ListAgents returns the configuration of every agent, sorted by ID
+func (f *FileRegistry) ListAgents() []*AgentConfig {
+	definitions := f.registry.ListAgents()
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID < definitions[j].ID
+	})
+
+	configs := make([]*AgentConfig, 0, len(definitions))
+	for _, definition := range definitions {
+		configs = append(configs, definitionToConfig(definition))
+	}
+	return configs
+}
+
+// AddAgent writes a new agent file to the rules directory and registers it.
+// The file content is taken from the SettingContent setting; content without frontmatter
+// gets one generated from the metadata.
+func (f *FileRegistry) AddAgent(config *AgentConfig) error {
+	id, content, err := f.PrepareAgent(config)
+	if err != nil {
+		return err
+	}
+	if f.registry.AgentExists(id) {
+		return fmt.Errorf("agent already exists: %s", id)
+	}
+
+	path := filepath.Join(f.registry.rulesDir, id+".mdc")
+	if utils.FileExists(path) {
+		return fmt.Errorf("file already exists: %s", path)
+	}
+	if err := f.writeAgentFile(path, content); err != nil {
+		return err
+	}
+
+	if err := f.register(id, path); err != nil {
+		// Keep the file so it can be fixed, but do not register a half-defined agent
+		return fmt.Errorf("agent written to %s but cannot be resolved: %w", path, err)
+	}
+	return nil
+}
+
+// ReplaceAgent writes an agent file like AddAgent, replacing the agent with the same ID if
+// there is one. The new file is moved over the old one only once it is complete, and the old
+// content is put back if the new agent cannot be registered, so a failed replacement leaves
+// the existing agent as it was.
+func (f *FileRegistry) ReplaceAgent(config *AgentConfig) error {
+	id, content, err := f.PrepareAgent(config)
+	if err != nil {
+		return err
+	}
+	existing, exists := f.registry.agents[id]
+	if !exists {
+		return f.AddAgent(config)
+	}
+
+	path := existing.DefinitionPath
+	if path == "" {
+		path = filepath.Join(f.registry.rulesDir, id+".mdc")
+	}
+	previous, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read agent file: %w", err)
+	}
+
+	if err := f.writeAgentFile(path, content); err != nil {
+		return err
+	}
+	if err := f.register(id, path); err != nil {
+		if previous != nil {
+			if restoreErr := f.writeAgentFile(path, string(previous)); restoreErr != nil {
+				return fmt.Errorf("agent %s cannot be resolved (%v) and restoring it failed: %w", id, err, restoreErr)
+			}
+		}
+		f.registry.agents[id] = existing
+		return fmt.Errorf("agent %s cannot be resolved, kept the previous version: %w", id, err)
+	}
+	return nil
+}
+
+// PrepareAgent validates an agent configuration and returns its ID and the content of its file,
+// without writing anything. Content that extends another agent is rendered against the installed
+// agents, so the file is complete on its own; the agent it extends must already be installed.
+func (f *FileRegistry) PrepareAgent(config *AgentConfig) (string, string, error) {
+	if config == nil {
+		return "", "", fmt.Errorf("agent config is nil")
+	}
+
+	id := config.Metadata.ID
+	if !validateAgentID(id) {
+		return "", "", fmt.Errorf("invalid agent ID: %q (IDs may not contain dots, slashes or spaces)", id)
+	}
+
+	content, _ := config.Settings[SettingContent].(string)
+	if strings.TrimSpace(content) == "" {
+		return "", "", fmt.Errorf("agent %s has no content", id)
+	}
+	block, _, ok := SplitFrontmatter(content)
+	if !ok {
+		metadata := config.Metadata
+		if metadata.Description == "" {
+			_, metadata.Description = extractAgentMetadata(content, id)
+		}
+		content = JoinFrontmatter(metadataFrontmatter(metadata), content)
+	} else if _, err := ParseFrontmatter(block); err != nil {
+		return "", "", fmt.Errorf("agent %s has invalid frontmatter: %w", id, err)
+	}
+
+	if parent, _ := ExtendsOf(content); parent != "" {
+		rendered, err := ResolveExtends(id, func(other string) (string, bool, error) {
+			if other == id {
+				return content, true, nil
+			}
+			return f.registry.readAgentFile(other)
+		})
+		if err != nil {
+			return "", "", err
+		}
+		content = rendered
+	}
+	return id, content, nil
+}
+
+// writeAgentFile writes an agent file through a temporary file in the same directory, so the
+// file at path is either left untouched or replaced completely
+func (f *FileRegistry) writeAgentFile(path, content string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, f.registry.config.DirPermission); err != nil {
+		return fmt.Errorf("failed to create rules directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".*.mdc.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write agent file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write agent file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write agent file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), f.registry.config.FilePermission); err != nil {
+		return fmt.Errorf("failed to write agent file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write agent file: %w", err)
+	}
+
+	utils.Debug("Agent file written | path=" + path)
+	return nil
+}
+
+// register parses a written agent file into the registry, resolving the agent it extends
+func (f *FileRegistry) register(id, path string) error {
+	if err := f.registry.processAgentFile(path); err != nil {
+		return err
+	}
+
+	definition := f.registry.agents[id]
+	if definition.Extends == "" {
+		return nil
+	}
+	rendered, err := f.registry.Render(id)
+	if err != nil {
+		delete(f.registry.agents, id)
+		return err
+	}
+	applyRenderedContent(definition, rendered)
+	return nil
+}
+
+// RemoveAgent deletes an agent file from the rules directory and unregisters it
+func (f *FileRegistry) RemoveAgent(id string) error {
+	definition, err := f.registry.GetAgent(id)
+	if err != nil {
+		return err
+	}
+
+	path := definition.DefinitionPath
+	if path == "" {
+		path = filepath.Join(f.registry.rulesDir, id+".mdc")
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete agent file: %w", err)
+	}
+
+	delete(f.registry.agents, id)
+	utils.Debug("Agent removed | id=" + id + ", path=" + path)
+	return nil
+}
+
+// definitionToConfig converts a parsed agent definition to the AgentRegistry representation
+func definitionToConfig(definition *AgentDefinition) *AgentConfig {
+	return &AgentConfig{
+		Metadata: AgentMetadata{
+			ID:          definition.ID,
+			Type:        definition.Type,
+			Version:     definition.Version,
+			Description: definition.Description,
+			Author:      definition.Author,
+			Tags:        definition.Tags,
+			Properties: map[string]interface{}{
+				"name":        definition.Name,
+				"category":    definition.Category,
+				"icon":        definition.Icon,
+				"globs":       definition.Globs,
+				"alwaysApply": definition.AlwaysApply,
+				"path":        definition.DefinitionPath,
+			},
+			LastUpdated: definition.LastUpdated,
+		},
+		Settings:  definition.Config,
+		Templates: definition.Templates,
+	}
+}
+
+// metadataFrontmatter builds a frontmatter block for an agent file from its metadata
+func metadataFrontmatter(metadata AgentMetadata) string {
+	lines := []string{
+		"description: " + FrontmatterValue(metadata.Description),
+		"globs: ",
+		"alwaysApply: false",
+	}
+	if metadata.Version != "" {
+		lines = append(lines, "version: "+metadata.Version)
+	}
+	if metadata.Author != "" {
+		lines = append(lines, "author: "+FrontmatterValue(metadata.Author))
+	}
+	if len(metadata.Tags) > 0 {
+		lines = append(lines, "tags: ["+strings.Join(metadata.Tags, ", ")+"]")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
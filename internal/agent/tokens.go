@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"unicode"
+)
+
+// charsPerToken is the average number of characters of an English word or code identifier per token
+const charsPerToken = 4
+
+// EstimateTokens approximates the number of tokens a model's tokenizer splits text into.
+// Words and numbers cost one token per four characters, punctuation one token per mark
+// (runs of the same mark, such as --- or ===, one per four), characters of scripts without
+// spaces such as Chinese one token each, and emoji two. Whitespace is free. Real counts
+// depend on the model, so this is only good for comparing agents and noticing when a
+// rule set outgrows a budget.
+func EstimateTokens(text string) int {
+	tokens := 0
+	word := 0       // Length of the current word
+	lastMark := ' ' // Previous punctuation mark
+	markRun := 0    // Length of the current run of that mark
+
+	flushWord := func() {
+		tokens += (word + charsPerToken - 1) / charsPerToken
+		word = 0
+	}
+	flushMarks := func() {
+		tokens += (markRun + charsPerToken - 1) / charsPerToken
+		markRun = 0
+	}
+
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxLatin1 && (unicode.IsLetter(r) || unicode.IsDigit(r)), r == '_':
+			flushMarks()
+			word++
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flushWord()
+			flushMarks()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Other scripts tokenize less efficiently than Latin ones
+			flushMarks()
+			word += 2
+		case unicode.IsSpace(r):
+			flushWord()
+			flushMarks()
+		case r > 0xFFFF || unicode.Is(unicode.So, r):
+			flushWord()
+			flushMarks()
+			tokens += 2
+		case unicode.Is(unicode.Mn, r) || r == 0xFE0F || r == 0x200D:
+			// Combining marks, emoji variation selectors and joiners belong to the previous character
+		default:
+			flushWord()
+			if markRun > 0 && r != lastMark {
+				flushMarks()
+			}
+			lastMark = r
+			markRun++
+		}
+	}
+	flushWord()
+	flushMarks()
+	return tokens
+}
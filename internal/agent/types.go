@@ -68,16 +68,40 @@ type AgentRegistry interface {
 
 // AgentDefinition represents a single agent's definition and metadata
 type AgentDefinition struct {
-	ID             string                 `json:"id"`
-	Name           string                 `json:"name"`
-	Description    string                 `json:"description"`
-	Version        string                 `json:"version"`
-	Type           string                 `json:"type"`
-	Config         map[string]interface{} `json:"config"`
-	Templates      []string               `json:"templates"`
-	LastUpdated    time.Time              `json:"last_updated"`
-	Content        string                 `json:"content,omitempty"`
-	DefinitionPath string                 `json:"definition_path,omitempty"`
+	ID                     string                 `json:"id"`
+	Name                   string                 `json:"name"`
+	Description            string                 `json:"description"`
+	FrontmatterDescription string                 `json:"frontmatter_description,omitempty"` // Description as declared in the frontmatter, which Cursor reads
+	Version                string                 `json:"version"`
+	Type                   string                 `json:"type"`
+	Config                 map[string]interface{} `json:"config"`
+	Templates              []string               `json:"templates"`
+	LastUpdated            time.Time              `json:"last_updated"`
+	Content                string                 `json:"content,omitempty"`
+	DefinitionPath         string                 `json:"definition_path,omitempty"`
+	Globs                  []string               `json:"globs,omitempty"`        // File patterns the rule auto-attaches to
+	AlwaysApply            bool                   `json:"always_apply,omitempty"` // Rule is included in every request
+	HasFrontmatter         bool                   `json:"has_frontmatter"`
+	Author                 string                 `json:"author,omitempty"`
+	Tags                   []string               `json:"tags,omitempty"`
+	Category               string                 `json:"category,omitempty"` // Declared category, empty to detect it from the ID
+	Icon                   string                 `json:"icon,omitempty"`
+	Extends                string                 `json:"extends,omitempty"` // ID of the agent this definition inherits from
+	Tokens                 int                    `json:"tokens,omitempty"`  // Estimated token count of the rule body, see EstimateTokens
+}
+
+// AttachMode describes when Cursor includes the rule, using Cursor's rule types
+func (d *AgentDefinition) AttachMode() string {
+	switch {
+	case d.AlwaysApply:
+		return AttachAlways
+	case len(d.Globs) > 0:
+		return AttachAuto
+	case d.FrontmatterDescription != "":
+		return AttachAgentRequested
+	default:
+		return AttachManual
+	}
 }
 
 // Agent represents a loaded and initialized agent
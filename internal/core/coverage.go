@@ -0,0 +1,173 @@
+package core
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// RuleCoverage is the number of project files an auto attached rule's globs match
+type RuleCoverage struct {
+	ID    string   `json:"id"`
+	Globs []string `json:"globs"`
+	Files int      `json:"files"`
+}
+
+// DirectoryCoverage summarizes the files of a directory and the rules attaching to them
+type DirectoryCoverage struct {
+	Path      string `json:"path"`
+	Files     int    `json:"files"`
+	Uncovered int    `json:"uncovered"` // Files no glob matches
+	Rules     int    `json:"rules"`     // Distinct rules whose globs match at least one of the files
+}
+
+// CoverageReport tells how the globs of a project's rules cover its files, apart from .cursor.
+// Rules with alwaysApply attach to every file and are listed apart, so they
+// do not hide files that no glob reaches.
+type CoverageReport struct {
+	Dir            string              `json:"dir"`
+	Files          int                 `json:"files"`
+	Covered        int                 `json:"covered"`
+	AlwaysRules    []string            `json:"alwaysRules"`
+	Rules          []RuleCoverage      `json:"rules"`
+	UnusedRules    []string            `json:"unusedRules"`    // Auto attached rules matching no file
+	UncoveredFiles []string            `json:"uncoveredFiles"` // Files no glob matches
+	Directories    []DirectoryCoverage `json:"directories"`
+}
+
+// RulesCoverage matches the globs of every rule in a registry against the files of a project,
+// skipping files ignored by git. Files are counted towards their directory cut off at depth
+// levels below the project root, so depth 1 summarizes by top-level directory; 0 keeps
+// every directory apart.
+func RulesCoverage(registry *agent.Registry, projectDir string, depth int) (*CoverageReport, error) {
+	ignore, err := utils.NewIgnoreMatcher(projectDir)
+	if err != nil {
+		return nil, wrapOpError("RulesCoverage", projectDir, err, "failed to read ignore files")
+	}
+
+	report := &CoverageReport{
+		Dir:            projectDir,
+		AlwaysRules:    []string{},
+		Rules:          []RuleCoverage{},
+		UnusedRules:    []string{},
+		UncoveredFiles: []string{},
+		Directories:    []DirectoryCoverage{},
+	}
+
+	definitions := registry.ListAgents()
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID < definitions[j].ID
+	})
+	var globRules []*agent.AgentDefinition
+	for _, definition := range definitions {
+		switch definition.AttachMode() {
+		case agent.AttachAlways:
+			report.AlwaysRules = append(report.AlwaysRules, definition.ID)
+		case agent.AttachAuto:
+			globRules = append(globRules, definition)
+		}
+	}
+
+	ruleFiles := make([]int, len(globRules))
+	directories := make(map[string]*DirectoryCoverage)
+	directoryRules := make(map[string]map[string]bool)
+
+	err = filepath.WalkDir(projectDir, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			utils.Debug("Skipping unreadable path | path=" + current + ", error=" + err.Error())
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if current == projectDir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectDir, current)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Rules and cursor++ state live in .cursor, which no rule is meant to cover
+		if ignore.Ignored(rel, entry.IsDir()) || rel == path.Dir(LockFilePath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		dir := coverageDirectory(rel, depth)
+		summary, ok := directories[dir]
+		if !ok {
+			summary = &DirectoryCoverage{Path: dir}
+			directories[dir] = summary
+			directoryRules[dir] = make(map[string]bool)
+		}
+		summary.Files++
+		report.Files++
+
+		covered := false
+		for i, definition := range globRules {
+			for _, glob := range definition.Globs {
+				if MatchGlob(glob, rel) {
+					ruleFiles[i]++
+					directoryRules[dir][definition.ID] = true
+					covered = true
+					break
+				}
+			}
+		}
+		if covered {
+			report.Covered++
+		} else {
+			summary.Uncovered++
+			report.UncoveredFiles = append(report.UncoveredFiles, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapOpError("RulesCoverage", projectDir, err, "failed to walk project")
+	}
+
+	for i, definition := range globRules {
+		report.Rules = append(report.Rules, RuleCoverage{ID: definition.ID, Globs: definition.Globs, Files: ruleFiles[i]})
+		if ruleFiles[i] == 0 {
+			report.UnusedRules = append(report.UnusedRules, definition.ID)
+		}
+	}
+
+	for dir, summary := range directories {
+		summary.Rules = len(directoryRules[dir])
+		report.Directories = append(report.Directories, *summary)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		return report.Directories[i].Path < report.Directories[j].Path
+	})
+
+	utils.Debugf("Computed rule coverage | files=%d covered=%d rules=%d unused=%d",
+		report.Files, report.Covered, len(report.Rules), len(report.UnusedRules))
+	return report, nil
+}
+
+// coverageDirectory returns the directory a file is counted towards, cut off at depth levels
+func coverageDirectory(file string, depth int) string {
+	dir := path.Dir(file)
+	if dir == "." || depth <= 0 {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
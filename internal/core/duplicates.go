@@ -0,0 +1,262 @@
+package core
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// Defaults for FindDuplicates
+const (
+	DefaultDuplicateDistance = 8 // Differing simhash bits up to which two blocks count as near duplicates
+	DefaultDuplicateMinWords = 8 // Blocks with fewer words are too generic to report
+)
+
+// listItem matches the marker of a Markdown list item
+var listItem = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+
+// ContentBlock is a paragraph or list item of an agent file
+type ContentBlock struct {
+	Agent   string `json:"agent"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Section string `json:"section,omitempty"` // Heading of the section the block is in
+	Text    string `json:"text"`
+	Tokens  int    `json:"tokens"`
+
+	words []string
+	hash  uint64
+}
+
+// DuplicateGroup is a set of blocks in different agents that say the same thing, at most one per agent
+type DuplicateGroup struct {
+	Identical    bool           `json:"identical"`    // All blocks have the same words
+	Distance     int            `json:"distance"`     // Largest simhash distance between blocks joined into the group
+	WastedTokens int            `json:"wastedTokens"` // Estimated tokens of every copy but the first
+	Blocks       []ContentBlock `json:"blocks"`
+}
+
+// DuplicateReport lists the duplicated and nearly duplicated blocks of a set of agents
+type DuplicateReport struct {
+	Agents       int              `json:"agents"`
+	Blocks       int              `json:"blocks"` // Blocks long enough to be compared
+	WastedTokens int              `json:"wastedTokens"`
+	Groups       []DuplicateGroup `json:"groups"`
+}
+
+// FindDuplicates compares the paragraphs and list items of every agent in a registry with those
+// of the other agents. Each block is reduced to a 64 bit simhash of its words and word pairs; blocks
+// whose hashes differ in at most maxDistance bits are near duplicates. Blocks shorter than minWords
+// are skipped. Agent files are read as written, so agents extending another one are compared by
+// their own content rather than what they inherit. Groups are sorted by wasted tokens.
+func FindDuplicates(registry *agent.Registry, maxDistance, minWords int) (*DuplicateReport, error) {
+	definitions := registry.ListAgents()
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID < definitions[j].ID
+	})
+
+	var blocks []ContentBlock
+	for _, definition := range definitions {
+		content, err := os.ReadFile(definition.DefinitionPath)
+		if err != nil {
+			return nil, wrapOpError("FindDuplicates", definition.DefinitionPath, err, "failed to read agent")
+		}
+		for _, block := range contentBlocks(string(content)) {
+			if len(block.words) < minWords {
+				continue
+			}
+			block.Agent = definition.ID
+			block.File = definition.DefinitionPath
+			block.Tokens = agent.EstimateTokens(block.Text)
+			block.hash = simhash(block.words)
+			blocks = append(blocks, block)
+		}
+	}
+
+	// Join every pair of similar blocks from different agents into groups
+	parent := make([]int, len(blocks))
+	distance := make([]int, len(blocks))
+	groupAgents := make([]map[string]bool, len(blocks)) // Agents with a block in the group, by root
+	for i := range parent {
+		parent[i] = i
+		groupAgents[i] = map[string]bool{blocks[i].Agent: true}
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range blocks {
+		for j := i + 1; j < len(blocks); j++ {
+			if blocks[i].Agent == blocks[j].Agent {
+				continue
+			}
+			d := bits.OnesCount64(blocks[i].hash ^ blocks[j].hash)
+			if d > maxDistance {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri != rj {
+				// A group holds one block per agent, so every copy it counts is in another agent
+				if sharesAgent(groupAgents[ri], groupAgents[rj]) {
+					continue
+				}
+				parent[rj] = ri
+				distance[ri] = max(distance[ri], distance[rj])
+				for id := range groupAgents[rj] {
+					groupAgents[ri][id] = true
+				}
+				groupAgents[rj] = nil
+			}
+			distance[ri] = max(distance[ri], d)
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range blocks {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	report := &DuplicateReport{Agents: len(definitions), Blocks: len(blocks), Groups: []DuplicateGroup{}}
+	for root, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Identical: true, Distance: distance[root]}
+		first := strings.Join(blocks[indexes[0]].words, " ")
+		for n, i := range indexes {
+			group.Blocks = append(group.Blocks, blocks[i])
+			if n > 0 {
+				group.WastedTokens += blocks[i].Tokens
+			}
+			if strings.Join(blocks[i].words, " ") != first {
+				group.Identical = false
+			}
+		}
+		report.WastedTokens += group.WastedTokens
+		report.Groups = append(report.Groups, group)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.WastedTokens != b.WastedTokens {
+			return a.WastedTokens > b.WastedTokens
+		}
+		return a.Blocks[0].Agent < b.Blocks[0].Agent ||
+			a.Blocks[0].Agent == b.Blocks[0].Agent && a.Blocks[0].Line < b.Blocks[0].Line
+	})
+
+	utils.Debugf("Compared agent content | agents=%d blocks=%d groups=%d", report.Agents, report.Blocks, len(report.Groups))
+	return report, nil
+}
+
+// sharesAgent reports whether two groups hold blocks from the same agent
+func sharesAgent(a, b map[string]bool) bool {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for id := range a {
+		if b[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// contentBlocks splits the body of an agent file into paragraphs and list items, each with the
+// line it starts on and the heading of its section. Headings, rules and code blocks are skipped.
+func contentBlocks(content string) []ContentBlock {
+	_, body, _ := agent.SplitFrontmatter(content)
+	offset := strings.Count(content[:len(content)-len(body)], "\n")
+
+	var blocks []ContentBlock
+	var current *ContentBlock
+	var lines []string
+	section := ""
+	var fence agent.CodeFence
+
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Join(lines, " ")
+			current.words = blockWords(current.Text)
+			blocks = append(blocks, *current)
+		}
+		current, lines = nil, nil
+	}
+
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence.Contains(line):
+			flush()
+			continue
+		case trimmed == "" || strings.Trim(trimmed, "-*_= ") == "":
+			flush()
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			section = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		case listItem.MatchString(line):
+			flush()
+			trimmed = strings.TrimSpace(listItem.ReplaceAllString(line, ""))
+		}
+
+		if current == nil {
+			current = &ContentBlock{Line: offset + i + 1, Section: section}
+		}
+		lines = append(lines, trimmed)
+	}
+	flush()
+	return blocks
+}
+
+// blockWords returns the lowercased words of a block, without Markdown markup and punctuation
+func blockWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// simhash computes a 64 bit similarity hash of words and adjacent word pairs.
+// Texts sharing most of their words and word order get hashes differing in few bits.
+func simhash(words []string) uint64 {
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	for i, word := range words {
+		add(word)
+		if i > 0 {
+			add(words[i-1] + " " + word)
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// Formats of other AI assistants that agents can be exported to
+const (
+	ExportCopilot  = "copilot"
+	ExportAgentsMD = "agents-md"
+	ExportWindsurf = "windsurf"
+	ExportCline    = "cline"
+	ExportMarkdown = "markdown"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{ExportCopilot, ExportAgentsMD, ExportWindsurf, ExportCline, ExportMarkdown}
+
+// exportPaths holds the file each format reads its instructions from, relative to the project root.
+// Plain Markdown has no conventional location.
+var exportPaths = map[string]string{
+	ExportCopilot:  ".github/copilot-instructions.md",
+	ExportAgentsMD: "AGENTS.md",
+	ExportWindsurf: ".windsurfrules",
+	ExportCline:    ".clinerules",
+	ExportMarkdown: "",
+}
+
+// copilotInstructionsDir holds Copilot's path-specific instruction files
+const copilotInstructionsDir = ".github/instructions"
+
+// ExportMarker starts the comment marking files written by export, so they can be regenerated
+const ExportMarker = "<!-- Generated by cursor++ export"
+
+// exportIDComment is the comment under each flattened agent's title that records its ID, so an
+// import gives the agent back its ID rather than one derived from the title
+const exportIDComment = "<!-- cursor++ agent: %s -->"
+
+// attachModeOrder is the order agents appear in in exported files
+var attachModeOrder = map[string]int{
+	agent.AttachAlways:         0,
+	agent.AttachAuto:           1,
+	agent.AttachAgentRequested: 2,
+	agent.AttachManual:         3,
+}
+
+// ExportFile is a file written by an export
+type ExportFile struct {
+	Path    string   `json:"path"`   // Slash separated and relative to the project root; empty for standard output
+	Agents  []string `json:"agents"` // IDs of the agents in the file
+	Content string   `json:"content"`
+}
+
+// ExportAgents converts the agents of a registry into the instruction files of another assistant.
+// Copilot gets the rules with globs as path-specific .instructions.md files with an applyTo
+// pattern, unless singleFile is set, and every other rule in copilot-instructions.md. The other
+// formats have no way to attach instructions to files, so their rules are flattened into one
+// Markdown file where each agent is a section whose first line says when it applies.
+func ExportAgents(registry *agent.Registry, format string, singleFile bool) ([]ExportFile, error) {
+	mainPath, known := exportPaths[format]
+	if !known {
+		return nil, wrapValidationError("format", fmt.Sprintf("unknown export format %q; expected one of %s",
+			format, strings.Join(ExportFormats, ", ")))
+	}
+
+	definitions := registry.ListAgents()
+	sort.Slice(definitions, func(i, j int) bool {
+		a, b := attachModeOrder[definitions[i].AttachMode()], attachModeOrder[definitions[j].AttachMode()]
+		if a != b {
+			return a < b
+		}
+		return definitions[i].ID < definitions[j].ID
+	})
+
+	marker := fmt.Sprintf("%s --format %s from the project's Cursor rules; edit those and export again rather than this file -->", ExportMarker, format)
+	main := ExportFile{Path: mainPath, Agents: []string{}}
+	sections := []string{marker, "# Project Instructions"}
+	var files []ExportFile
+
+	for _, definition := range definitions {
+		content, err := registry.Render(definition.ID)
+		if err != nil {
+			return nil, wrapOpError("ExportAgents", definition.ID, err, "failed to read agent")
+		}
+		_, body, _ := agent.SplitFrontmatter(content)
+		body = strings.Trim(body, "\n")
+
+		if format == ExportCopilot && !singleFile && definition.AttachMode() == agent.AttachAuto {
+			files = append(files, copilotInstructions(definition, body, marker))
+			continue
+		}
+		main.Agents = append(main.Agents, definition.ID)
+		sections = append(sections, flattenAgent(definition, body))
+	}
+
+	main.Content = strings.Join(sections, "\n\n") + "\n"
+	files = append([]ExportFile{main}, files...)
+
+	utils.Debugf("Exported agents | format=%s agents=%d files=%d", format, len(definitions), len(files))
+	return files, nil
+}
+
+// copilotInstructions turns a rule with globs into a Copilot path-specific instructions file
+func copilotInstructions(definition *agent.AgentDefinition, body, marker string) ExportFile {
+	var patterns []string
+	for _, glob := range definition.Globs {
+		patterns = append(patterns, normalizeGlob(glob)...)
+	}
+
+	var block strings.Builder
+	fmt.Fprintf(&block, "applyTo: %q\n", strings.Join(patterns, ","))
+	if definition.Description != "" {
+		fmt.Fprintf(&block, "description: %q\n", definition.Description)
+	}
+
+	return ExportFile{
+		Path:    copilotInstructionsDir + "/" + definition.ID + ".instructions.md",
+		Agents:  []string{definition.ID},
+		Content: agent.JoinFrontmatter(block.String(), marker+"\n\n"+body+"\n"),
+	}
+}
+
+// flattenAgent turns an agent into a section of a single instructions file. The agent's title
+// becomes a second level heading, with its other headings moved down a level to match. A comment
+// with the agent's ID and a note saying when the rule applies follow the title, since the target
+// cannot attach it itself.
+func flattenAgent(definition *agent.AgentDefinition, body string) string {
+	lines := strings.Split(body, "\n")
+	title := "## " + definition.Name
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		title = "#" + lines[0]
+		lines = lines[1:]
+	}
+
+	var note string
+	switch definition.AttachMode() {
+	case agent.AttachAuto:
+		var patterns []string
+		for _, glob := range definition.Globs {
+			for _, pattern := range normalizeGlob(glob) {
+				patterns = append(patterns, "`"+pattern+"`")
+			}
+		}
+		note = "_Applies when working on files matching " + strings.Join(patterns, ", ") + "._"
+	case agent.AttachAgentRequested:
+		// Descriptions copied from the body would only repeat it
+		if strings.Contains(body, definition.FrontmatterDescription) {
+			note = "_Applies when relevant to the task at hand._"
+		} else {
+			note = "_Applies when relevant: " + strings.TrimSuffix(definition.FrontmatterDescription, ".") + "._"
+		}
+	case agent.AttachManual:
+		note = "_Applies only when asked for " + definition.Name + " (`@" + definition.ID + "` in Cursor)._"
+	}
+
+	var fence agent.CodeFence
+	for i, line := range lines {
+		if !fence.Contains(line) && strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "######") {
+			lines[i] = "#" + line
+		}
+	}
+	// A code block left open would swallow the agents after this one
+	if closing := fence.Closing(); closing != "" {
+		lines = append(lines, closing)
+	}
+
+	section := title + "\n" + fmt.Sprintf(exportIDComment, definition.ID)
+	if note != "" {
+		section += "\n\n" + note
+	}
+	if rest := strings.Trim(strings.Join(lines, "\n"), "\n"); rest != "" {
+		section += "\n\n" + rest
+	}
+	return section
+}
+
+// StaleExportFiles returns the Copilot instruction files of a project written by an earlier export
+// whose agents are no longer exported with globs, as slash separated paths relative to projectDir
+func StaleExportFiles(projectDir string, files []ExportFile) ([]string, error) {
+	current := make(map[string]bool, len(files))
+	for _, file := range files {
+		current[file.Path] = true
+	}
+
+	paths, err := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(copilotInstructionsDir), "*.instructions.md"))
+	if err != nil {
+		return nil, wrapOpError("StaleExportFiles", projectDir, err, "failed to list instruction files")
+	}
+
+	var stale []string
+	for _, path := range paths {
+		rel := copilotInstructionsDir + "/" + filepath.Base(path)
+		if current[rel] {
+			continue
+		}
+		if exported, err := IsExportedFile(path); err != nil {
+			return nil, err
+		} else if exported {
+			stale = append(stale, rel)
+		}
+	}
+	return stale, nil
+}
+
+// IsExportedFile reports whether a file was written by export, judging by its marker comment
+func IsExportedFile(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, wrapOpError("IsExportedFile", path, err, "failed to read file")
+	}
+	return strings.Contains(string(content), ExportMarker), nil
+}
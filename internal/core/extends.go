@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// renderExtendedAgents replaces agents that extend another agent with their rendered content,
+// so projects receive complete definitions that follow changes to the parent.
+// files maps paths relative to the rules directory to source files, as returned by overlaySources,
+// and is updated in place to point at rendered copies in a temporary directory.
+// The returned cleanup function removes the copies once they are no longer needed.
+func (ai *AgentInitializer) renderExtendedAgents(files map[string]string) (func(), error) {
+	byID := make(map[string]string, len(files))
+	for relPath := range files {
+		byID[strings.TrimSuffix(filepath.Base(relPath), ".mdc")] = relPath
+	}
+
+	lookup := func(id string) (string, bool, error) {
+		relPath, ok := byID[id]
+		if !ok {
+			return "", false, nil
+		}
+		content, err := os.ReadFile(files[relPath])
+		if err != nil {
+			return "", false, wrapOpError("renderExtendedAgents", files[relPath], err, "failed to read agent definition")
+		}
+		return string(content), true, nil
+	}
+
+	rendered := make(map[string]string)
+	var problems []string
+	for _, relPath := range sortedKeys(files) {
+		id := strings.TrimSuffix(filepath.Base(relPath), ".mdc")
+		content, _, err := lookup(id)
+		if err != nil {
+			return nil, err
+		}
+		if parent, err := agent.ExtendsOf(content); err != nil || parent == "" {
+			continue
+		}
+
+		// Collect every broken agent so they can all be fixed at once
+		result, err := agent.ResolveExtends(id, lookup)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		rendered[relPath] = result
+	}
+
+	if len(problems) > 0 {
+		return nil, wrapValidationError("extends", strings.Join(problems, "; "))
+	}
+	if len(rendered) == 0 {
+		return func() {}, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "cursor++-extends-")
+	if err != nil {
+		return nil, wrapOpError("renderExtendedAgents", "", err, "failed to create temporary directory")
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	for relPath, content := range rendered {
+		path := filepath.Join(tempDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(path), ai.config.DirPermission); err != nil {
+			cleanup()
+			return nil, wrapOpError("renderExtendedAgents", path, err, "failed to create directory")
+		}
+		if err := os.WriteFile(path, []byte(content), ai.config.FilePermission); err != nil {
+			cleanup()
+			return nil, wrapOpError("renderExtendedAgents", path, err, "failed to write rendered agent")
+		}
+		files[relPath] = path
+	}
+
+	utils.Debug(fmt.Sprintf("Rendered extending agents | count=%d dir=%s", len(rendered), tempDir))
+	return cleanup, nil
+}
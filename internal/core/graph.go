@@ -0,0 +1,245 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// GraphNode is an installed agent in an agent graph
+type GraphNode struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// GraphEdge is a reference from one agent to another, located at its first mention
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Line int    `json:"line"`
+}
+
+// AgentGraph holds the handoffs between agents, found in their content as @id or @id.mdc
+type AgentGraph struct {
+	Nodes    []GraphNode `json:"nodes"`
+	Edges    []GraphEdge `json:"edges"`    // References between installed agents
+	Dangling []GraphEdge `json:"dangling"` // References to agents that are not installed
+	Orphans  []string    `json:"orphans"`  // Installed agents no other agent hands off to
+}
+
+// BuildAgentGraph collects the references between the agents of a registry.
+// Agents that extend another agent are read in their rendered form; references to
+// the agent itself are ignored.
+func BuildAgentGraph(registry *agent.Registry) (*AgentGraph, error) {
+	definitions := registry.ListAgents()
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID < definitions[j].ID
+	})
+
+	graph := &AgentGraph{Edges: []GraphEdge{}, Dangling: []GraphEdge{}, Orphans: []string{}}
+	referenced := make(map[string]bool)
+
+	for _, definition := range definitions {
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			ID:       definition.ID,
+			Name:     definition.Name,
+			Category: ui.DetectAgentCategory(definition),
+		})
+
+		content, err := registry.Render(definition.ID)
+		if err != nil {
+			return nil, wrapOpError("BuildAgentGraph", definition.ID, err, "failed to read agent")
+		}
+
+		seen := make(map[string]bool)
+		for _, ref := range agent.FindReferences(content) {
+			if ref.Target == definition.ID || seen[ref.Target] {
+				continue
+			}
+			seen[ref.Target] = true
+
+			edge := GraphEdge{From: definition.ID, To: ref.Target, Line: ref.Line}
+			if registry.AgentExists(ref.Target) {
+				graph.Edges = append(graph.Edges, edge)
+				referenced[ref.Target] = true
+			} else {
+				graph.Dangling = append(graph.Dangling, edge)
+			}
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		if !referenced[node.ID] {
+			graph.Orphans = append(graph.Orphans, node.ID)
+		}
+	}
+
+	utils.Debugf("Built agent graph | agents=%d edges=%d dangling=%d orphans=%d",
+		len(graph.Nodes), len(graph.Edges), len(graph.Dangling), len(graph.Orphans))
+	return graph, nil
+}
+
+// missingTargets returns the IDs of the agents referenced but not installed, sorted
+func (g *AgentGraph) missingTargets() []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for _, edge := range g.Dangling {
+		if !seen[edge.To] {
+			seen[edge.To] = true
+			targets = append(targets, edge.To)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// categories returns the node categories in sorted order with their nodes
+func (g *AgentGraph) categories() ([]string, map[string][]GraphNode) {
+	byCategory := make(map[string][]GraphNode)
+	for _, node := range g.Nodes {
+		byCategory[node.Category] = append(byCategory[node.Category], node)
+	}
+	names := make([]string, 0, len(byCategory))
+	for name := range byCategory {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, byCategory
+}
+
+// problemComments describes dangling references and orphans, one line each, for output as comments
+func (g *AgentGraph) problemComments() []string {
+	var lines []string
+	if len(g.Dangling) > 0 {
+		lines = append(lines, "Dangling references (agent not installed):")
+		for _, edge := range g.Dangling {
+			lines = append(lines, fmt.Sprintf("  %s -> %s (line %d)", edge.From, edge.To, edge.Line))
+		}
+	}
+	if len(g.Orphans) > 0 {
+		lines = append(lines, "Agents no other agent hands off to: "+strings.Join(g.Orphans, ", "))
+	}
+	return lines
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Agents are clustered by category,
+// missing agents are drawn dashed in red and orphans in orange.
+func (g *AgentGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("// Generated by cursor++ agent graph\n")
+	for _, line := range g.problemComments() {
+		b.WriteString("// " + line + "\n")
+	}
+	b.WriteString("digraph agents {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+
+	orphans := make(map[string]bool, len(g.Orphans))
+	for _, id := range g.Orphans {
+		orphans[id] = true
+	}
+
+	names, byCategory := g.categories()
+	for i, category := range names {
+		fmt.Fprintf(&b, "\n  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(category))
+		for _, node := range byCategory[category] {
+			attrs := "label=" + dotQuote(node.Name)
+			if orphans[node.ID] {
+				attrs += `, color="orange"`
+			}
+			fmt.Fprintf(&b, "    %s [%s];\n", dotQuote(node.ID), attrs)
+		}
+		b.WriteString("  }\n")
+	}
+
+	if missing := g.missingTargets(); len(missing) > 0 {
+		b.WriteString("\n")
+		for _, id := range missing {
+			fmt.Fprintf(&b, "  %s [label=%s, style=\"rounded,dashed\", color=\"red\", fontcolor=\"red\"];\n",
+				dotQuote(id), dotQuote("@"+id+"\n(not installed)"))
+		}
+	}
+
+	b.WriteString("\n")
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	for _, edge := range g.Dangling {
+		fmt.Fprintf(&b, "  %s -> %s [style=dashed, color=\"red\"];\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart with a subgraph per category.
+// Missing agents are drawn dashed in red and orphans with an orange border.
+func (g *AgentGraph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("%% Generated by cursor++ agent graph; do not edit by hand\n")
+	for _, line := range g.problemComments() {
+		b.WriteString("%% " + line + "\n")
+	}
+	b.WriteString("graph TD\n")
+
+	names, byCategory := g.categories()
+	for i, category := range names {
+		fmt.Fprintf(&b, "    subgraph category%d[\"%s\"]\n", i, mermaidEscape(category))
+		for _, node := range byCategory[category] {
+			fmt.Fprintf(&b, "        %s[\"%s\"]\n", mermaidID(node.ID), mermaidEscape(node.Name))
+		}
+		b.WriteString("    end\n")
+	}
+
+	missing := g.missingTargets()
+	for _, id := range missing {
+		fmt.Fprintf(&b, "    %s[\"@%s (not installed)\"]:::missing\n", mermaidID(id), mermaidEscape(id))
+	}
+
+	b.WriteString("\n")
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "    %s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+	}
+	for _, edge := range g.Dangling {
+		fmt.Fprintf(&b, "    %s -.-> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+	}
+
+	b.WriteString("\n")
+	b.WriteString("    classDef missing fill:#fff0f0,stroke:#cc0000,stroke-width:2px,stroke-dasharray: 5 5,color:#cc0000\n")
+	b.WriteString("    classDef orphan stroke:#ff9900,stroke-width:3px\n")
+	if len(g.Orphans) > 0 {
+		ids := make([]string, 0, len(g.Orphans))
+		for _, id := range g.Orphans {
+			ids = append(ids, mermaidID(id))
+		}
+		fmt.Fprintf(&b, "    class %s orphan\n", strings.Join(ids, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes a DOT identifier or label
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + strings.ReplaceAll(value, "\n", `\n`) + `"`
+}
+
+// mermaidID turns an agent ID into a Mermaid node ID; hyphens could be read as part of an arrow
+func mermaidID(id string) string {
+	return strings.ReplaceAll(id, "-", "_")
+}
+
+// mermaidEscape escapes a Mermaid label
+func mermaidEscape(value string) string {
+	return strings.ReplaceAll(value, `"`, "#quot;")
+}
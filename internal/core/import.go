@@ -0,0 +1,408 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// ImportCursorRules is the legacy single-file .cursorrules format, which can be imported but not exported
+const ImportCursorRules = "cursorrules"
+
+// ImportFormats lists the formats instructions can be imported from
+var ImportFormats = []string{ExportCopilot, ExportAgentsMD, ExportWindsurf, ExportCline, ImportCursorRules}
+
+// defaultImportID names the agent made of instructions that come before any heading
+const defaultImportID = "project-rules"
+
+// maxImportIDLength keeps IDs derived from long headings readable
+const maxImportIDLength = 60
+
+// heading matches a Markdown ATX heading, capturing its markers and text
+var heading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// exportNote matches the line export puts under each agent title to say when it applies
+var exportNote = regexp.MustCompile(`^_Applies (.*)\._$`)
+
+// exportedID matches the comment export puts under each agent title to record its ID
+var exportedID = regexp.MustCompile(`^<!-- cursor\+\+ agent: (\S+) -->$`)
+
+// manualReference matches the agent ID export names in the note of an agent attached only when asked for.
+// Exports that predate the ID comment only name the IDs of these agents.
+var manualReference = regexp.MustCompile("`@([^`]+)` in Cursor")
+
+// ImportedAgent is an agent split out of another assistant's instruction file
+type ImportedAgent struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Source      string   `json:"source"` // File the agent was read from
+	Line        int      `json:"line"`   // Line of the heading the agent starts at in Source
+	Description string   `json:"description,omitempty"`
+	Globs       []string `json:"globs,omitempty"`
+	AlwaysApply bool     `json:"alwaysApply"`
+	Content     string   `json:"content"` // Complete .mdc file, frontmatter included
+}
+
+// ImportSourcePaths returns the files of a project that hold the instructions of an import format.
+// For Copilot these are copilot-instructions.md and the path-specific .instructions.md files.
+func ImportSourcePaths(projectDir, format string) ([]string, error) {
+	var paths []string
+	switch format {
+	case ImportCursorRules:
+		paths = []string{".cursorrules"}
+	case ExportCopilot:
+		paths = []string{exportPaths[format]}
+		matches, err := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(copilotInstructionsDir), "*.instructions.md"))
+		if err != nil {
+			return nil, wrapOpError("ImportSourcePaths", projectDir, err, "failed to list instruction files")
+		}
+		for _, match := range matches {
+			paths = append(paths, copilotInstructionsDir+"/"+filepath.Base(match))
+		}
+	case ExportAgentsMD, ExportWindsurf, ExportCline:
+		paths = []string{exportPaths[format]}
+	default:
+		return nil, wrapValidationError("format", fmt.Sprintf("unknown import format %q; expected one of %s",
+			format, strings.Join(ImportFormats, ", ")))
+	}
+
+	var existing []string
+	for _, path := range paths {
+		full := filepath.Join(projectDir, filepath.FromSlash(path))
+		if _, err := os.Stat(full); err == nil {
+			existing = append(existing, full)
+		}
+	}
+	if len(existing) == 0 {
+		return nil, wrapNotFoundError("instructions file", paths[0])
+	}
+	return existing, nil
+}
+
+// DetectImportFormat guesses the import format of a file from its name, returning an empty string
+// if the name is not one any format uses
+func DetectImportFormat(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case base == ".cursorrules":
+		return ImportCursorRules
+	case base == "copilot-instructions.md" || strings.HasSuffix(base, ".instructions.md"):
+		return ExportCopilot
+	case strings.EqualFold(base, "AGENTS.md"):
+		return ExportAgentsMD
+	case base == ".windsurfrules":
+		return ExportWindsurf
+	case base == ".clinerules":
+		return ExportCline
+	}
+	return ""
+}
+
+// ImportAgents splits instruction files into agents. Each file is cut at the shallowest heading
+// level that occurs more than once, so every topic becomes an agent named after its heading, with
+// its own headings moved up to match; text before the first topic becomes an agent of its own.
+// Copilot .instructions.md files become one agent each, with their applyTo pattern as globs, and a
+// directory, as .clinerules may be, has each of its Markdown files imported. The notes export puts
+// under each agent are turned back into frontmatter. Other agents get the first sentence of their
+// text as description, and alwaysApply if requested. IDs are derived from the headings, made
+// unique and checked with agent.IsValidAgentID.
+func ImportAgents(paths []string, alwaysApply bool) ([]ImportedAgent, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, wrapOpError("ImportAgents", path, err, "failed to read instructions")
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.md"))
+		if err != nil {
+			return nil, wrapOpError("ImportAgents", path, err, "failed to list instruction files")
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	var imported []ImportedAgent
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, wrapOpError("ImportAgents", file, err, "failed to read instructions")
+		}
+
+		var agents []ImportedAgent
+		if strings.HasSuffix(file, ".instructions.md") {
+			single, err := copilotInstructionsAgent(file, string(content))
+			if err != nil {
+				return nil, err
+			}
+			agents = []ImportedAgent{single}
+		} else {
+			agents = splitInstructions(string(content))
+		}
+
+		for i := range agents {
+			agents[i].Source = file
+			if alwaysApply && len(agents[i].Globs) == 0 {
+				agents[i].AlwaysApply = true
+			}
+		}
+		imported = append(imported, agents...)
+	}
+
+	used := make(map[string]bool, len(imported))
+	for i := range imported {
+		id := imported[i].ID
+		for n := 2; used[id]; n++ {
+			id = imported[i].ID + "-" + strconv.Itoa(n)
+		}
+		if !agent.IsValidAgentID(id) {
+			return nil, wrapValidationError("id", fmt.Sprintf("cannot derive a valid agent ID for %q (%s:%d)",
+				imported[i].Name, imported[i].Source, imported[i].Line))
+		}
+		used[id] = true
+		imported[i].ID = id
+		imported[i].Content = importedContent(&imported[i])
+	}
+
+	utils.Debugf("Imported instructions | files=%d agents=%d", len(files), len(imported))
+	return imported, nil
+}
+
+// splitInstructions splits the content of an instruction file into agents. Source is left for the
+// caller to fill in, and Content holds the body without frontmatter.
+func splitInstructions(content string) []ImportedAgent {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimPrefix(content, "\ufeff"), "\r\n", "\n"), "\n")
+
+	type section struct {
+		line  int // Index of the heading line, -1 for the preamble
+		level int
+		title string
+	}
+
+	// Find the headings outside code blocks and pick the level to split at
+	var headings []section
+	counts := make(map[int]int)
+	var fence agent.CodeFence
+	for i, line := range lines {
+		if fence.Contains(line) {
+			continue
+		}
+		if match := heading.FindStringSubmatch(line); match != nil {
+			headings = append(headings, section{line: i, level: len(match[1]), title: match[2]})
+			counts[len(match[1])]++
+		}
+	}
+	splitLevel := 0
+	for level := 1; level <= 6 && splitLevel == 0; level++ {
+		if counts[level] > 1 {
+			splitLevel = level
+		}
+	}
+
+	// A lone heading above the topics, or of an unsplit file, is the title of the whole file
+	title, titleLine := "", -1
+	if len(headings) > 0 && (splitLevel == 0 || headings[0].level < splitLevel) {
+		title, titleLine = headings[0].title, headings[0].line
+	}
+
+	sections := []section{{line: -1, title: title}}
+	for _, h := range headings {
+		if splitLevel > 0 && h.level <= splitLevel && h.line != titleLine {
+			sections = append(sections, h)
+		}
+	}
+
+	var agents []ImportedAgent
+	for n, s := range sections {
+		end := len(lines)
+		if n+1 < len(sections) {
+			end = sections[n+1].line
+		}
+		start := s.line + 1
+		if s.line == -1 {
+			start = 0
+		}
+
+		var body []string
+		fence = agent.CodeFence{}
+		for i := start; i < end; i++ {
+			line := lines[i]
+			inFence := fence.Contains(line)
+			switch {
+			case i == titleLine, !inFence && strings.HasPrefix(strings.TrimSpace(line), ExportMarker):
+				continue
+			case !inFence && splitLevel > 1 && heading.MatchString(line):
+				// Move the topic's headings up so the topic heading becomes the title
+				level := len(heading.FindStringSubmatch(line)[1])
+				line = strings.Repeat("#", max(level-splitLevel+1, 1)) + strings.TrimLeft(line, "#")
+			}
+			body = append(body, line)
+		}
+
+		imported := ImportedAgent{Name: s.title, Line: s.line + 1}
+		if s.line == -1 {
+			imported.Line = max(titleLine+1, 1)
+		}
+		text := strings.Trim(strings.Join(body, "\n"), "\n ")
+		if text == "" {
+			continue
+		}
+		text, manual := applyExportNote(&imported, text)
+		if imported.Name == "" {
+			imported.Name = "Project Rules"
+		}
+		if imported.Description == "" && len(imported.Globs) == 0 && !manual {
+			imported.Description = firstSentence(text)
+		}
+
+		if imported.ID == "" {
+			imported.ID = importID(imported.Name)
+		}
+		imported.Content = "# " + imported.Name + "\n\n" + strings.TrimLeft(text, "\n") + "\n"
+		agents = append(agents, imported)
+	}
+	return agents
+}
+
+// applyExportNote reads the ID comment and the note export writes under an agent's title back
+// into the agent's ID and frontmatter settings. It returns the text without them, and whether the
+// note says the agent is only attached when asked for.
+func applyExportNote(imported *ImportedAgent, text string) (string, bool) {
+	first, rest, _ := strings.Cut(text, "\n")
+	if match := exportedID.FindStringSubmatch(strings.TrimSpace(first)); match != nil {
+		imported.ID = match[1]
+		text = strings.TrimLeft(rest, "\n")
+		first, rest, _ = strings.Cut(text, "\n")
+	}
+	match := exportNote.FindStringSubmatch(strings.TrimSpace(first))
+	if match == nil {
+		return text, false
+	}
+	rest = strings.TrimLeft(rest, "\n")
+
+	note := match[1]
+	switch {
+	case strings.HasPrefix(note, "when working on files matching "):
+		// Globs are quoted in backticks, so they are every other part
+		parts := strings.Split(note, "`")
+		for i := 1; i < len(parts); i += 2 {
+			imported.Globs = append(imported.Globs, parts[i])
+		}
+	case strings.HasPrefix(note, "when relevant: "):
+		imported.Description = strings.TrimPrefix(note, "when relevant: ") + "."
+	case strings.HasPrefix(note, "only when asked for "):
+		if match := manualReference.FindStringSubmatch(note); match != nil && imported.ID == "" {
+			imported.ID = match[1]
+		}
+		return rest, true
+	case note == "when relevant to the task at hand":
+	default:
+		return text, false
+	}
+	return rest, false
+}
+
+// copilotInstructionsAgent turns a Copilot path-specific instructions file into a single agent
+func copilotInstructionsAgent(path, content string) (ImportedAgent, error) {
+	block, body, _ := agent.SplitFrontmatter(content)
+	fm, err := agent.ParseFrontmatter(block)
+	if err != nil {
+		return ImportedAgent{}, wrapParseError(path, err, 1)
+	}
+
+	id := strings.TrimSuffix(filepath.Base(path), ".instructions.md")
+	imported := ImportedAgent{ID: importID(id), Name: agent.DefaultAgentName(id), Line: 1, Description: fm.Get("description")}
+	for _, pattern := range agent.SplitList(fm.Get("applyTo")) {
+		if pattern == "**" || pattern == "**/*" {
+			imported.AlwaysApply = true
+			continue
+		}
+		imported.Globs = append(imported.Globs, pattern)
+	}
+	if imported.AlwaysApply {
+		imported.Globs = nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), ExportMarker) {
+			lines = append(lines, line)
+		}
+	}
+	text := strings.Trim(strings.Join(lines, "\n"), "\n ")
+	if match := heading.FindStringSubmatch(strings.SplitN(text, "\n", 2)[0]); match != nil && len(match[1]) == 1 {
+		imported.Name = match[2]
+	} else {
+		text = "# " + imported.Name + "\n\n" + text
+	}
+	if imported.Description == "" && len(imported.Globs) == 0 {
+		imported.Description = firstSentence(text)
+	}
+	imported.Content = text + "\n"
+	return imported, nil
+}
+
+// importedContent puts the frontmatter of an imported agent in front of its body
+func importedContent(imported *ImportedAgent) string {
+	var block strings.Builder
+	block.WriteString("description: " + agent.FrontmatterValue(imported.Description) + "\n")
+	block.WriteString("globs: " + strings.Join(imported.Globs, ", ") + "\n")
+	block.WriteString("alwaysApply: " + strconv.FormatBool(imported.AlwaysApply) + "\n")
+	return agent.JoinFrontmatter(block.String(), imported.Content)
+}
+
+// importID derives an agent ID from a heading: its letters and digits, lowercased and joined by
+// dashes, cut at a word boundary when long
+func importID(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	id := ""
+	for _, word := range words {
+		if id != "" && len(id)+1+len(word) > maxImportIDLength {
+			break
+		}
+		if id != "" {
+			id += "-"
+		}
+		id += word
+	}
+	if len(id) > maxImportIDLength {
+		id = id[:maxImportIDLength]
+	}
+	if id == "" {
+		return defaultImportID
+	}
+	return id
+}
+
+// firstSentence returns the first sentence of the first paragraph of Markdown text, as a description
+func firstSentence(text string) string {
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" || heading.MatchString(paragraph) || strings.HasPrefix(paragraph, "```") {
+			continue
+		}
+		paragraph = strings.Join(strings.Fields(listItem.ReplaceAllString(paragraph, "")), " ")
+		paragraph = strings.NewReplacer("**", "", "__", "", "`", "").Replace(paragraph)
+		if end := strings.Index(paragraph, ". "); end != -1 {
+			paragraph = paragraph[:end+1]
+		}
+		if runes := []rune(paragraph); len(runes) > 200 {
+			paragraph = string(runes[:199]) + "…"
+		}
+		return paragraph
+	}
+	return ""
+}
@@ -0,0 +1,296 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// FileAction describes what a sync does to a single rule file
+type FileAction string
+
+// Possible file actions, decided by comparing the local, upstream and baseline contents
+const (
+	ActionCreate    FileAction = "create"     // File did not exist in the project
+	ActionOverwrite FileAction = "overwrite"  // Only upstream changed
+	ActionUnchanged FileAction = "unchanged"  // Local copy already matches upstream
+	ActionKeepLocal FileAction = "keep-local" // Only the user changed the file
+	ActionMerge     FileAction = "merge"      // Both changed without overlapping
+	ActionConflict  FileAction = "conflict"   // Both changed the same lines
+	ActionBackup    FileAction = "backup"     // No baseline to merge against; local copy saved aside
+	ActionRemove    FileAction = "remove"     // No longer installed and unmodified; deleted
+	ActionDetach    FileAction = "detach"     // No longer installed but modified locally; left in place
+)
+
+// origSuffix is appended to a rule file to keep the local version when it cannot be merged cleanly
+const origSuffix = ".orig"
+
+// FileChange records the action taken for a single rule file during a sync
+type FileChange struct {
+	Path      string     // Path relative to the rules directory
+	Action    FileAction // Action taken for the file
+	Conflicts int        // Number of conflicting hunks for ActionConflict
+	Hash      string     // Checksum of the upstream content
+}
+
+// Changed reports whether the action modified the project's copy of the file
+func (c FileChange) Changed() bool {
+	return c.Action != ActionUnchanged && c.Action != ActionKeepLocal && c.Action != ActionDetach
+}
+
+// writeLockFile pins the sources and files installed into a project
+func (ai *AgentInitializer) writeLockFile(projectDir string, sources []*resolvedSource, changes []FileChange) error {
+	lock := newLockFile(sources, changes)
+	return lock.Save(projectDir, ai.config)
+}
+
+// syncRules installs rule files into a project's rules directory.
+// files maps paths relative to the rules directory to the upstream file to install, already
+// filtered by the project's agent selection. A non-nil selection is remembered for later syncs.
+// Local edits are preserved with a three-way merge against the baseline recorded in the manifest,
+// and previously installed files that are no longer part of the install are removed if unmodified.
+func (ai *AgentInitializer) syncRules(files map[string]string, projectDir string, selection *Selection) ([]FileChange, error) {
+	targetDir := filepath.Join(projectDir, ai.config.RulesDirName)
+	if err := os.MkdirAll(targetDir, ai.config.DirPermission); err != nil {
+		return nil, wrapOpError("syncRules", targetDir, err, "failed to create target directory")
+	}
+
+	manifest, err := LoadManifest(projectDir, ai.config)
+	if err != nil {
+		return nil, err
+	}
+	if selection != nil {
+		manifest.Selection = nil
+		if !selection.IsEmpty() {
+			manifest.Selection = selection
+		}
+	}
+
+	planned, err := ai.planSync(manifest, files, targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]FileChange, 0, len(planned))
+	for _, file := range planned {
+		if err := ai.applyFileUpdate(filepath.Join(targetDir, file.relPath), file.update); err != nil {
+			return changes, err
+		}
+		changes = append(changes, file.update.change)
+
+		// The upstream version becomes the baseline for the next sync
+		if file.upstream != nil {
+			err = manifest.SetBase(file.relPath, file.upstream)
+		} else {
+			err = manifest.RemoveBase(file.relPath)
+		}
+		if err != nil {
+			return changes, err
+		}
+	}
+
+	if err := manifest.Save(); err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
+
+// plannedFile pairs a rule file with the update decided for it
+type plannedFile struct {
+	relPath  string
+	upstream []byte // Upstream content, nil if the file is no longer installed
+	update   fileUpdate
+}
+
+// planSync decides what a sync does to every file.
+// Files tracked by the manifest that are not installed anymore are planned for removal.
+func (ai *AgentInitializer) planSync(manifest *Manifest, files map[string]string, targetDir string) ([]plannedFile, error) {
+	planned := make([]plannedFile, 0, len(files))
+	for _, relPath := range sortedKeys(files) {
+		upstream, err := os.ReadFile(files[relPath])
+		if err != nil {
+			return nil, wrapOpError("planSync", files[relPath], err, "failed to read agent definition")
+		}
+
+		update, err := ai.planFile(manifest, relPath, upstream, filepath.Join(targetDir, relPath))
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, plannedFile{relPath: relPath, upstream: upstream, update: update})
+	}
+
+	for _, relPath := range manifest.TrackedFiles() {
+		if _, installed := files[relPath]; installed {
+			continue
+		}
+
+		update, err := ai.planRemoval(manifest, relPath, filepath.Join(targetDir, relPath))
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, plannedFile{relPath: relPath, update: update})
+	}
+
+	return planned, nil
+}
+
+// fileUpdate is the outcome decided for a single rule file before anything is written
+type fileUpdate struct {
+	change  FileChange
+	local   []byte // Current content in the project, nil if the file does not exist
+	content []byte // Content to write to the rule file, nil if it is left alone
+	backup  []byte // Content to save aside as the .orig file, nil if none
+	remove  bool   // Whether the rule file is deleted
+}
+
+// planFile decides how a single rule file is brought up to date with its upstream content
+func (ai *AgentInitializer) planFile(manifest *Manifest, relPath string, upstream []byte, targetPath string) (fileUpdate, error) {
+	update := fileUpdate{change: FileChange{Path: relPath, Hash: utils.HashBytes(upstream)}}
+
+	local, err := os.ReadFile(targetPath)
+	if os.IsNotExist(err) {
+		update.change.Action = ActionCreate
+		update.content = upstream
+		return update, nil
+	}
+	if err != nil {
+		return update, wrapOpError("planFile", targetPath, err, "failed to read local rule file")
+	}
+	update.local = local
+
+	if bytes.Equal(local, upstream) {
+		update.change.Action = ActionUnchanged
+		return update, nil
+	}
+
+	base, hasBase := manifest.ReadBase(relPath)
+	if !hasBase {
+		// Without a baseline we cannot tell who changed what, so keep both versions
+		utils.Debug("No baseline for modified rule file, keeping local copy aside | path=" + relPath)
+		update.change.Action = ActionBackup
+		update.backup = local
+		update.content = upstream
+		return update, nil
+	}
+
+	localChanged := !bytes.Equal(local, base)
+	upstreamChanged := !bytes.Equal(upstream, base)
+
+	switch {
+	case !upstreamChanged:
+		update.change.Action = ActionKeepLocal
+		return update, nil
+	case !localChanged:
+		update.change.Action = ActionOverwrite
+		update.content = upstream
+		return update, nil
+	}
+
+	result := Merge3(string(base), string(local), string(upstream))
+	update.content = []byte(result.Content)
+	if result.Conflicts == 0 {
+		update.change.Action = ActionMerge
+		return update, nil
+	}
+
+	update.change.Action = ActionConflict
+	update.change.Conflicts = result.Conflicts
+	update.backup = local
+	return update, nil
+}
+
+// planRemoval decides what happens to a tracked rule file that is no longer installed.
+// Unmodified files are deleted; files the user edited are left in place and no longer tracked.
+func (ai *AgentInitializer) planRemoval(manifest *Manifest, relPath string, targetPath string) (fileUpdate, error) {
+	update := fileUpdate{change: FileChange{Path: relPath}}
+
+	local, err := os.ReadFile(targetPath)
+	if os.IsNotExist(err) {
+		update.change.Action = ActionRemove
+		return update, nil
+	}
+	if err != nil {
+		return update, wrapOpError("planRemoval", targetPath, err, "failed to read local rule file")
+	}
+	update.local = local
+
+	base, hasBase := manifest.ReadBase(relPath)
+	if !hasBase || !bytes.Equal(local, base) {
+		update.change.Action = ActionDetach
+		return update, nil
+	}
+
+	update.change.Action = ActionRemove
+	update.remove = true
+	return update, nil
+}
+
+// applyFileUpdate writes the outcome of a planned update to a rule file
+func (ai *AgentInitializer) applyFileUpdate(targetPath string, update fileUpdate) error {
+	if update.backup != nil {
+		if err := ai.writeRuleFile(targetPath+origSuffix, update.backup); err != nil {
+			return err
+		}
+	}
+	if update.content != nil {
+		if err := ai.writeRuleFile(targetPath, update.content); err != nil {
+			return err
+		}
+	}
+	if update.remove {
+		if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+			return wrapOpError("applyFileUpdate", targetPath, err, "failed to remove rule file")
+		}
+	}
+	return nil
+}
+
+// writeRuleFile writes content to a rule file, creating parent directories as needed
+func (ai *AgentInitializer) writeRuleFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), ai.config.DirPermission); err != nil {
+		return wrapOpError("writeRuleFile", path, err, "failed to create directory")
+	}
+	if err := os.WriteFile(path, content, ai.config.FilePermission); err != nil {
+		return wrapOpError("writeRuleFile", path, err, "failed to write rule file")
+	}
+	return nil
+}
+
+// reportFileChanges tells the user about files that need their attention after a sync
+func reportFileChanges(changes []FileChange) {
+	for _, change := range changes {
+		switch change.Action {
+		case ActionKeepLocal:
+			ui.Info("Kept local changes to %s", change.Path)
+		case ActionMerge:
+			ui.Info("Merged local and upstream changes in %s", change.Path)
+		case ActionConflict:
+			ui.Warning("%d conflict(s) in %s; resolve the markers (your version is in %s%s)",
+				change.Conflicts, change.Path, change.Path, origSuffix)
+		case ActionBackup:
+			ui.Warning("Replaced locally modified %s; your version was saved to %s%s",
+				change.Path, change.Path, origSuffix)
+		case ActionRemove:
+			ui.Info("Removed %s, it is no longer installed", change.Path)
+		case ActionDetach:
+			ui.Warning("%s is no longer installed but has local changes; it was left in place", change.Path)
+		}
+	}
+}
+
+// summarizeFileChanges counts the changed and conflicting files of a sync
+func summarizeFileChanges(changes []FileChange) (changed int, conflicts int) {
+	for _, change := range changes {
+		if change.Changed() {
+			changed++
+		}
+		if change.Action == ActionConflict {
+			conflicts++
+		}
+	}
+	return changed, conflicts
+}
@@ -0,0 +1,364 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// Lint severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// LintRule describes a check performed by LintRules
+type LintRule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+// LintRuleSet lists every check LintRules performs
+var LintRuleSet = []LintRule{
+	{"frontmatter-missing", SeverityWarning, "Rule file has no frontmatter block"},
+	{"frontmatter-syntax", SeverityError, "Frontmatter block cannot be parsed"},
+	{"glob-invalid", SeverityError, "Glob in frontmatter is not a valid pattern"},
+	{"title-missing", SeverityError, "Rule file has no '# ' title heading"},
+	{"role-missing", SeverityError, "Rule file has neither a '## 🎯 Role:' section nor a frontmatter description"},
+	{"role-empty", SeverityWarning, "Line after the Role heading is empty, so no description is shown"},
+	{"id-invalid", SeverityError, "File name is not a valid agent ID"},
+	{"id-duplicate", SeverityError, "Agent ID is defined by more than one file"},
+	{"file-too-large", SeverityError, "Rule file exceeds the maximum file size"},
+	{"reference-broken", SeverityError, "@agent.mdc reference points to an agent that does not exist"},
+	{"extends-missing", SeverityError, "Agent extends an agent that does not exist"},
+	{"extends-cycle", SeverityError, "Agent is part of an inheritance cycle"},
+}
+
+// roleHeading is the heading agent.extractAgentMetadata reads the description after
+const roleHeading = "## 🎯 Role:"
+
+// LintDiagnostic is a single problem found in a rule file
+type LintDiagnostic struct {
+	File     string `json:"file"` // Path relative to the linted directory
+	Line     int    `json:"line,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintReport holds the result of linting a rules directory
+type LintReport struct {
+	Dir         string           `json:"dir"`
+	Files       int              `json:"files"`
+	Errors      int              `json:"errors"`
+	Warnings    int              `json:"warnings"`
+	Diagnostics []LintDiagnostic `json:"diagnostics"`
+}
+
+// add records a diagnostic using the severity of its rule
+func (r *LintReport) add(file string, line int, rule string, format string, args ...interface{}) {
+	severity := SeverityError
+	for _, known := range LintRuleSet {
+		if known.ID == rule {
+			severity = known.Severity
+			break
+		}
+	}
+
+	if severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Diagnostics = append(r.Diagnostics, LintDiagnostic{
+		File:     file,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// LintRules checks every .mdc file below dir for problems that would make cursor++ or
+// Cursor read it wrongly. Files larger than config.MaxFileSize are reported but not parsed.
+func LintRules(dir string, config ParserConfig) (*LintReport, error) {
+	if !utils.DirExists(dir) {
+		return nil, wrapNotFoundError("rules directory", dir)
+	}
+
+	files, err := utils.CollectMDCFiles(dir)
+	if err != nil {
+		return nil, wrapOpError("LintRules", dir, err, "failed to list rule files")
+	}
+
+	report := &LintReport{Dir: dir, Files: len(files), Diagnostics: []LintDiagnostic{}}
+
+	relPaths := make([]string, 0, len(files))
+	byID := make(map[string][]string)
+	for relPath := range files {
+		relPaths = append(relPaths, relPath)
+		id := strings.TrimSuffix(filepath.Base(relPath), ".mdc")
+		byID[id] = append(byID[id], relPath)
+	}
+	sort.Strings(relPaths)
+
+	lookup := func(id string) (string, bool, error) {
+		paths, ok := byID[id]
+		if !ok {
+			return "", false, nil
+		}
+		content, err := os.ReadFile(files[paths[0]])
+		if err != nil {
+			return "", false, err
+		}
+		return string(content), true, nil
+	}
+
+	for _, relPath := range relPaths {
+		id := strings.TrimSuffix(filepath.Base(relPath), ".mdc")
+		file := filepath.ToSlash(relPath)
+
+		if !agent.IsValidAgentID(id) {
+			report.add(file, 0, "id-invalid", "%q is not a valid agent ID; IDs may not contain dots, slashes or spaces", id)
+		}
+		if others := byID[id]; len(others) > 1 {
+			report.add(file, 0, "id-duplicate", "agent ID %q is also defined by %s", id, strings.Join(otherPaths(others, relPath), ", "))
+		}
+
+		info, err := os.Stat(files[relPath])
+		if err != nil {
+			return nil, wrapOpError("LintRules", files[relPath], err, "failed to stat rule file")
+		}
+		if info.Size() > config.MaxFileSize {
+			report.add(file, 0, "file-too-large", "file is %d bytes, more than the maximum of %d", info.Size(), config.MaxFileSize)
+			continue
+		}
+
+		content, err := os.ReadFile(files[relPath])
+		if err != nil {
+			return nil, wrapOpError("LintRules", files[relPath], err, "failed to read rule file")
+		}
+		inherited := lintExtends(report, file, id, string(content), lookup)
+		lintContent(report, file, string(content), byID, inherited)
+	}
+
+	sort.SliceStable(report.Diagnostics, func(i, j int) bool {
+		a, b := report.Diagnostics[i], report.Diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	utils.Debugf("Linted rules | dir=%s files=%d errors=%d warnings=%d", dir, report.Files, report.Errors, report.Warnings)
+	return report, nil
+}
+
+// lintExtends checks that the agent a rule file extends can be resolved.
+// It reports whether the file extends another agent, which then provides its title and Role section.
+func lintExtends(report *LintReport, file, id, content string, lookup agent.AgentLookup) bool {
+	block, _, ok := agent.SplitFrontmatter(content)
+	if !ok {
+		return false
+	}
+	fm, err := agent.ParseFrontmatter(block)
+	if err != nil || fm.Get(agent.ExtendsKey) == "" {
+		return false
+	}
+
+	line := frontmatterKeyLine(block, agent.ExtendsKey)
+	_, err = agent.ResolveExtends(id, lookup)
+	switch {
+	case err == nil:
+	case errors.Is(err, agent.ErrExtendsCycle):
+		report.add(file, line, "extends-cycle", "%v", err)
+	case errors.Is(err, agent.ErrParentNotFound):
+		report.add(file, line, "extends-missing", "%v", err)
+	default:
+		// Syntax errors in the parent are reported for the parent itself
+		utils.Debugf("Cannot resolve agent inheritance | file=%s error=%v", file, err)
+	}
+	return true
+}
+
+// lintContent checks the frontmatter, required sections and references of a single rule file.
+// Title and Role checks are skipped for inherited files, which take them from their parent.
+// A description in the frontmatter stands in for the Role section, as imported rules have no Role.
+func lintContent(report *LintReport, file, content string, ids map[string][]string, inherited bool) {
+	block, body, hasFrontmatter := agent.SplitFrontmatter(content)
+	bodyStart := 1
+	described := false
+
+	switch {
+	case hasFrontmatter:
+		bodyStart = strings.Count(content[:len(content)-len(body)], "\n") + 1
+		lintFrontmatter(report, file, block)
+		if fm, err := agent.ParseFrontmatter(block); err == nil {
+			described = fm.Get("description") != ""
+		}
+	case strings.HasPrefix(strings.TrimSpace(firstLine(content)), "---"):
+		report.add(file, 1, "frontmatter-syntax", "frontmatter block is not closed with a --- line")
+	default:
+		report.add(file, 1, "frontmatter-missing", "no frontmatter block; Cursor will treat the rule as manual")
+	}
+
+	var titleFound, roleFound bool
+	var fence agent.CodeFence
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lineNo := bodyStart + i
+		trimmed := strings.TrimSpace(line)
+
+		if fence.Contains(line) {
+			continue
+		}
+
+		if !titleFound && strings.HasPrefix(trimmed, "# ") {
+			titleFound = true
+		}
+		if !roleFound && strings.HasPrefix(trimmed, roleHeading) {
+			roleFound = true
+			if i+1 >= len(lines) || strings.TrimSpace(lines[i+1]) == "" {
+				report.add(file, lineNo, "role-empty", "the line after %q should describe the role; it is used as the description", roleHeading)
+			}
+		}
+	}
+
+	for _, ref := range agent.FindReferences(body) {
+		if _, exists := ids[ref.Target]; !exists {
+			report.add(file, bodyStart+ref.Line-1, "reference-broken", "@%s.mdc refers to an agent that does not exist", ref.Target)
+		}
+	}
+
+	if inherited {
+		return
+	}
+	if !titleFound {
+		report.add(file, bodyStart, "title-missing", "no '# ' title heading; the file name is shown as the agent name")
+	}
+	if !roleFound && !described {
+		report.add(file, bodyStart, "role-missing", "no %q section; the agent has no description", roleHeading)
+	}
+}
+
+// lintFrontmatter checks the syntax of a frontmatter block and the globs it declares
+func lintFrontmatter(report *LintReport, file, block string) {
+	fm, err := agent.ParseFrontmatter(block)
+	if err != nil {
+		report.add(file, frontmatterErrorLine(err), "frontmatter-syntax", "%v", err)
+		return
+	}
+
+	if _, err := fm.Bool("alwaysApply"); err != nil {
+		report.add(file, frontmatterKeyLine(block, "alwaysApply"), "frontmatter-syntax", "%v", err)
+	}
+
+	for _, glob := range fm.List("globs") {
+		if err := validateGlob(glob); err != nil {
+			report.add(file, frontmatterKeyLine(block, "globs"), "glob-invalid", "glob %q: %v", glob, err)
+		}
+	}
+}
+
+// validateGlob checks a glob as Cursor understands it: path.Match syntax plus ** and {a,b} alternatives
+func validateGlob(glob string) error {
+	if strings.TrimSpace(glob) != glob {
+		return fmt.Errorf("leading or trailing whitespace")
+	}
+
+	depth := 0
+	for _, r := range glob {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unmatched '}'")
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unmatched '{'")
+	}
+
+	for _, alternative := range expandBraces(glob) {
+		if _, err := path.Match(strings.ReplaceAll(alternative, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid pattern syntax")
+		}
+	}
+	return nil
+}
+
+// expandBraces expands {a,b} alternatives in a glob into every combination
+func expandBraces(glob string) []string {
+	start := strings.Index(glob, "{")
+	if start == -1 {
+		return []string{glob}
+	}
+
+	depth := 0
+	for i := start; i < len(glob); i++ {
+		switch glob[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				var expanded []string
+				for _, option := range agent.SplitList(glob[start+1 : i]) {
+					expanded = append(expanded, expandBraces(glob[:start]+option+glob[i+1:])...)
+				}
+				return expanded
+			}
+		}
+	}
+	return []string{glob}
+}
+
+// frontmatterErrorLine extracts the file line number from a ParseFrontmatter error
+func frontmatterErrorLine(err error) int {
+	var line int
+	if _, scanErr := fmt.Sscanf(err.Error(), "line %d:", &line); scanErr != nil {
+		return 1
+	}
+	return line
+}
+
+// frontmatterKeyLine returns the file line number of a key in a frontmatter block
+func frontmatterKeyLine(block, key string) int {
+	scanner := bufio.NewScanner(strings.NewReader(block))
+	for line := 2; scanner.Scan(); line++ {
+		if k, _, found := strings.Cut(scanner.Text(), ":"); found && strings.TrimSpace(k) == key {
+			return line
+		}
+	}
+	return 1
+}
+
+// firstLine returns the first line of content
+func firstLine(content string) string {
+	line, _, _ := strings.Cut(content, "\n")
+	return line
+}
+
+// otherPaths returns paths without exclude
+func otherPaths(paths []string, exclude string) []string {
+	var others []string
+	for _, p := range paths {
+		if p != exclude {
+			others = append(others, filepath.ToSlash(p))
+		}
+	}
+	sort.Strings(others)
+	return others
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"cursor++/internal/utils"
+)
+
+const (
+	// LockFilePath is the location of the lockfile relative to the project root
+	LockFilePath = ".cursor/cursor++.lock"
+
+	// lockFileVersion is the current lockfile format version
+	lockFileVersion = 2
+)
+
+// LockFile pins the rule sources and commits a project was initialized from
+type LockFile struct {
+	Version int               `json:"version"`
+	Sources []LockedSource    `json:"sources"` // Rule sources in ascending priority
+	Files   map[string]string `json:"files"`   // Checksum of each installed file by relative path
+
+	// Single-source fields written by version 1 lockfiles, migrated into Sources on load
+	Source       string `json:"source,omitempty"`
+	Commit       string `json:"commit,omitempty"`
+	SourceFolder string `json:"sourceFolder,omitempty"`
+}
+
+// LockedSource pins a single rule source
+type LockedSource struct {
+	Name      string `json:"name"`
+	URL       string `json:"url,omitempty"`       // Git repository of the source
+	Path      string `json:"path,omitempty"`      // Local directory of the source
+	Commit    string `json:"commit,omitempty"`    // Resolved commit SHA, git sources only
+	Subfolder string `json:"subfolder,omitempty"` // Subfolder the rules were copied from
+	Priority  int    `json:"priority"`
+}
+
+// LoadLockFile reads the lockfile of a project
+func LoadLockFile(projectDir string) (*LockFile, error) {
+	path := filepath.Join(projectDir, LockFilePath)
+	if !utils.FileExists(path) {
+		return nil, wrapNotFoundError("lockfile", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrapOpError("LoadLockFile", path, err, "failed to read lockfile")
+	}
+
+	lock := &LockFile{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, wrapParseError(path, err, 0)
+	}
+
+	if lock.Version > lockFileVersion {
+		return nil, wrapValidationError("version", fmt.Sprintf("lockfile version %d is newer than supported version %d", lock.Version, lockFileVersion))
+	}
+
+	// Version 1 lockfiles pinned the default source only
+	if len(lock.Sources) == 0 && lock.Commit != "" {
+		lock.Sources = []LockedSource{{
+			Name:      utils.DefaultSourceName,
+			URL:       lock.Source,
+			Commit:    lock.Commit,
+			Subfolder: lock.SourceFolder,
+		}}
+		lock.Source, lock.Commit, lock.SourceFolder = "", "", ""
+	}
+
+	if len(lock.Sources) == 0 {
+		return nil, wrapValidationError("sources", "lockfile does not pin any source")
+	}
+	for _, source := range lock.Sources {
+		if source.URL != "" && source.Commit == "" {
+			return nil, wrapValidationError("commit", fmt.Sprintf("lockfile does not pin a commit for source '%s'", source.Name))
+		}
+	}
+
+	utils.Debugf("Lockfile loaded | path=%s sources=%d files=%d", path, len(lock.Sources), len(lock.Files))
+	return lock, nil
+}
+
+// Save writes the lockfile into a project
+func (l *LockFile) Save(projectDir string, config *utils.Config) error {
+	path := filepath.Join(projectDir, LockFilePath)
+	if err := os.MkdirAll(filepath.Dir(path), config.DirPermission); err != nil {
+		return wrapOpError("Save", path, err, "failed to create lockfile directory")
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return wrapOpError("Save", path, err, "failed to marshal lockfile")
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), config.FilePermission); err != nil {
+		return wrapOpError("Save", path, err, "failed to write lockfile")
+	}
+
+	utils.Debug("Lockfile saved | path=" + path)
+	return nil
+}
+
+// Verify checks that the overlaid rule files match the checksums pinned by the lockfile.
+// files maps paths relative to the rules directory to the files that would be installed.
+func (l *LockFile) Verify(files map[string]string) error {
+	var mismatched []string
+	for relPath, want := range l.Files {
+		path, ok := files[relPath]
+		if !ok {
+			mismatched = append(mismatched, relPath+" (missing)")
+			continue
+		}
+		got, err := utils.HashFile(path)
+		if err != nil {
+			return wrapOpError("Verify", path, err, "failed to checksum agent definition")
+		}
+		if got != want {
+			mismatched = append(mismatched, relPath+" (checksum mismatch)")
+		}
+	}
+	for relPath := range files {
+		if _, ok := l.Files[relPath]; !ok {
+			mismatched = append(mismatched, relPath+" (not in lockfile)")
+		}
+	}
+
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return wrapValidationError("lockfile", fmt.Sprintf("source does not match lockfile: %v", mismatched))
+	}
+	return nil
+}
+
+// RuleSources returns the pinned sources in the form used by the configuration
+func (l *LockFile) RuleSources() []utils.RuleSource {
+	sources := make([]utils.RuleSource, 0, len(l.Sources))
+	for _, locked := range l.Sources {
+		sources = append(sources, utils.RuleSource{
+			Name:      locked.Name,
+			URL:       locked.URL,
+			Path:      locked.Path,
+			Subfolder: locked.Subfolder,
+			Priority:  locked.Priority,
+		})
+	}
+	return sources
+}
+
+// commitFor returns the commit pinned for a source, or an empty string if it is not pinned
+func (l *LockFile) commitFor(name string) string {
+	for _, locked := range l.Sources {
+		if locked.Name == name {
+			return locked.Commit
+		}
+	}
+	return ""
+}
+
+// newLockFile builds a lockfile from the sources and files installed by a sync
+func newLockFile(sources []*resolvedSource, changes []FileChange) *LockFile {
+	files := make(map[string]string, len(changes))
+	for _, change := range changes {
+		// Removed and detached files carry no upstream checksum
+		if change.Hash != "" {
+			files[change.Path] = change.Hash
+		}
+	}
+
+	locked := make([]LockedSource, 0, len(sources))
+	for _, rs := range sources {
+		locked = append(locked, LockedSource{
+			Name:      rs.source.Name,
+			URL:       rs.source.URL,
+			Path:      rs.source.Path,
+			Commit:    rs.commit,
+			Subfolder: rs.source.Subfolder,
+			Priority:  rs.source.Priority,
+		})
+	}
+
+	return &LockFile{
+		Version: lockFileVersion,
+		Sources: locked,
+		Files:   files,
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"cursor++/internal/utils"
+)
+
+const (
+	// StateDirName is the per-project directory holding cursor++ bookkeeping, relative to the project root.
+	// It lives outside the rules directory so Cursor never loads its contents as rules.
+	StateDirName = ".cursor/cursor++"
+
+	// ManifestFileName is the name of the install manifest inside the state directory
+	ManifestFileName = "manifest.json"
+
+	// baseDirName holds the baseline copy of every installed file inside the state directory
+	baseDirName = "base"
+
+	// manifestVersion is the current manifest format version
+	manifestVersion = 1
+)
+
+// ManifestEntry records the baseline of a single installed rule file
+type ManifestEntry struct {
+	Hash        string    `json:"hash"`        // Checksum of the upstream content last installed
+	InstalledAt time.Time `json:"installedAt"` // When the baseline was recorded
+}
+
+// Manifest tracks the rule files cursor++ installed into a project
+type Manifest struct {
+	Version   int                      `json:"version"`
+	Files     map[string]ManifestEntry `json:"files"`
+	Selection *Selection               `json:"selection,omitempty"` // Agents chosen for the project, nil for all
+	dir       string                   // state directory of the project
+	config    *utils.Config
+}
+
+// LoadManifest loads the install manifest of a project, returning an empty one if none exists yet
+func LoadManifest(projectDir string, config *utils.Config) (*Manifest, error) {
+	manifest := &Manifest{
+		Version: manifestVersion,
+		Files:   make(map[string]ManifestEntry),
+		dir:     filepath.Join(projectDir, StateDirName),
+		config:  config,
+	}
+
+	path := manifest.path()
+	if !utils.FileExists(path) {
+		utils.Debug("Manifest does not exist yet | path=" + path)
+		return manifest, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrapOpError("LoadManifest", path, err, "failed to read manifest")
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, wrapParseError(path, err, 0)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]ManifestEntry)
+	}
+
+	utils.Debugf("Manifest loaded | path=%s files=%d", path, len(manifest.Files))
+	return manifest, nil
+}
+
+// path returns the location of the manifest file
+func (m *Manifest) path() string {
+	return filepath.Join(m.dir, ManifestFileName)
+}
+
+// basePath returns the location of the baseline copy of a rule file
+func (m *Manifest) basePath(relPath string) string {
+	return filepath.Join(m.dir, baseDirName, relPath)
+}
+
+// ReadBase returns the baseline content of a rule file, or false if no baseline was recorded
+func (m *Manifest) ReadBase(relPath string) ([]byte, bool) {
+	entry, tracked := m.Files[relPath]
+	if !tracked {
+		return nil, false
+	}
+
+	content, err := os.ReadFile(m.basePath(relPath))
+	if err != nil {
+		utils.Warn("Baseline copy missing | path=" + relPath + ", error=" + err.Error())
+		return nil, false
+	}
+
+	// A tampered baseline is worse than none at all
+	if utils.HashBytes(content) != entry.Hash {
+		utils.Warn("Baseline copy does not match manifest | path=" + relPath)
+		return nil, false
+	}
+	return content, true
+}
+
+// SetBase records content as the new baseline of a rule file
+func (m *Manifest) SetBase(relPath string, content []byte) error {
+	basePath := m.basePath(relPath)
+	if err := os.MkdirAll(filepath.Dir(basePath), m.config.DirPermission); err != nil {
+		return wrapOpError("SetBase", basePath, err, "failed to create baseline directory")
+	}
+	if err := os.WriteFile(basePath, content, m.config.FilePermission); err != nil {
+		return wrapOpError("SetBase", basePath, err, "failed to write baseline copy")
+	}
+
+	m.Files[relPath] = ManifestEntry{
+		Hash:        utils.HashBytes(content),
+		InstalledAt: time.Now(),
+	}
+	return nil
+}
+
+// RemoveBase stops tracking a rule file and deletes its baseline copy
+func (m *Manifest) RemoveBase(relPath string) error {
+	delete(m.Files, relPath)
+
+	basePath := m.basePath(relPath)
+	if err := os.Remove(basePath); err != nil && !os.IsNotExist(err) {
+		return wrapOpError("RemoveBase", basePath, err, "failed to remove baseline copy")
+	}
+	return nil
+}
+
+// TrackedFiles returns the paths of all tracked rule files in sorted order
+func (m *Manifest) TrackedFiles() []string {
+	paths := make([]string, 0, len(m.Files))
+	for relPath := range m.Files {
+		paths = append(paths, relPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Save writes the manifest to disk
+func (m *Manifest) Save() error {
+	path := m.path()
+	if err := os.MkdirAll(m.dir, m.config.DirPermission); err != nil {
+		return wrapOpError("Save", m.dir, err, "failed to create state directory")
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return wrapOpError("Save", path, err, "failed to marshal manifest")
+	}
+
+	if err := os.WriteFile(path, data, m.config.FilePermission); err != nil {
+		return wrapOpError("Save", path, err, "failed to write manifest")
+	}
+
+	utils.Debug("Manifest saved | path=" + path)
+	return nil
+}
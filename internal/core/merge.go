@@ -0,0 +1,261 @@
+package core
+
+import (
+	"strings"
+
+	"cursor++/internal/agent"
+)
+
+// Conflict marker labels written around overlapping changes
+const (
+	conflictStartMarker = "<<<<<<< local"
+	conflictSepMarker   = "======="
+	conflictEndMarker   = ">>>>>>> upstream"
+)
+
+// MergeResult holds the outcome of a three-way merge
+type MergeResult struct {
+	Content   string // Merged content, including conflict markers if any
+	Conflicts int    // Number of conflicting hunks
+}
+
+// Merge3 performs a line based three-way merge of local and upstream changes against a common base.
+// Non-overlapping changes are combined; overlapping ones are wrapped in conflict markers.
+// MDC frontmatter is merged key by key so that edits to different keys never conflict.
+func Merge3(base, local, upstream string) MergeResult {
+	baseFront, baseBody, baseOK := agent.SplitFrontmatter(base)
+	localFront, localBody, localOK := agent.SplitFrontmatter(local)
+	upstreamFront, upstreamBody, upstreamOK := agent.SplitFrontmatter(upstream)
+	if !baseOK || !localOK || !upstreamOK {
+		return mergeLines(base, local, upstream)
+	}
+
+	front, frontConflicts, ok := mergeFrontmatter(baseFront, localFront, upstreamFront)
+	if !ok {
+		return mergeLines(base, local, upstream)
+	}
+
+	body := mergeLines(baseBody, localBody, upstreamBody)
+	return MergeResult{
+		Content:   agent.JoinFrontmatter(front, body.Content),
+		Conflicts: frontConflicts + body.Conflicts,
+	}
+}
+
+// mergeFrontmatter merges frontmatter blocks key by key, keeping the key order of the local version.
+// Keys changed on both sides get conflict markers around them. ok is false if a block cannot be parsed.
+func mergeFrontmatter(base, local, upstream string) (string, int, bool) {
+	baseFM, err := agent.ParseFrontmatter(base)
+	if err != nil {
+		return "", 0, false
+	}
+	localFM, err := agent.ParseFrontmatter(local)
+	if err != nil {
+		return "", 0, false
+	}
+	upstreamFM, err := agent.ParseFrontmatter(upstream)
+	if err != nil {
+		return "", 0, false
+	}
+
+	// Local keys first, then keys only upstream has, in upstream order
+	keys := localFM.Keys()
+	for _, key := range upstreamFM.Keys() {
+		if !localFM.Has(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	var out []string
+	conflicts := 0
+
+	// Comments before the first key are kept from the local version
+	if len(localFM.Entries) > 0 && localFM.Entries[0].Key == "" {
+		out = append(out, localFM.Entries[0].Lines...)
+	}
+
+	for _, key := range keys {
+		baseRaw, inBase := baseFM.Raw(key)
+		localRaw, inLocal := localFM.Raw(key)
+		upstreamRaw, inUpstream := upstreamFM.Raw(key)
+
+		var chosen string
+		var keep bool
+		switch {
+		case inLocal == inUpstream && localRaw == upstreamRaw:
+			chosen, keep = localRaw, inLocal
+		case inLocal == inBase && localRaw == baseRaw:
+			chosen, keep = upstreamRaw, inUpstream
+		case inUpstream == inBase && upstreamRaw == baseRaw:
+			chosen, keep = localRaw, inLocal
+		default:
+			conflicts++
+			out = append(out, conflictStartMarker)
+			out = append(out, splitLines(localRaw)...)
+			out = append(out, conflictSepMarker)
+			out = append(out, splitLines(upstreamRaw)...)
+			out = append(out, conflictEndMarker)
+			continue
+		}
+
+		if keep {
+			out = append(out, strings.Split(chosen, "\n")...)
+		}
+	}
+
+	if len(out) == 0 {
+		return "", conflicts, true
+	}
+	return strings.Join(out, "\n") + "\n", conflicts, true
+}
+
+// mergeLines performs the line based three-way merge behind Merge3
+func mergeLines(base, local, upstream string) MergeResult {
+	baseLines := splitLines(base)
+	localLines := splitLines(local)
+	upstreamLines := splitLines(upstream)
+
+	matchLocal := matchLines(baseLines, localLines)
+	matchUpstream := matchLines(baseLines, upstreamLines)
+
+	var out []string
+	conflicts := 0
+	o, a, b := 0, 0, 0
+
+	for {
+		// Consume lines that are unchanged in both versions
+		k := 0
+		for o+k < len(baseLines) && a+k < len(localLines) && b+k < len(upstreamLines) &&
+			matchLocal[o+k] == a+k && matchUpstream[o+k] == b+k {
+			k++
+		}
+		if k > 0 {
+			out = append(out, baseLines[o:o+k]...)
+			o, a, b = o+k, a+k, b+k
+			continue
+		}
+
+		// Find the next base line that both versions still share
+		next := -1
+		for i := o; i < len(baseLines); i++ {
+			if matchLocal[i] >= a && matchUpstream[i] >= b {
+				next = i
+				break
+			}
+		}
+
+		var baseChunk, localChunk, upstreamChunk []string
+		if next == -1 {
+			baseChunk, localChunk, upstreamChunk = baseLines[o:], localLines[a:], upstreamLines[b:]
+		} else {
+			baseChunk = baseLines[o:next]
+			localChunk = localLines[a:matchLocal[next]]
+			upstreamChunk = upstreamLines[b:matchUpstream[next]]
+		}
+
+		switch {
+		case equalLines(localChunk, baseChunk):
+			out = append(out, upstreamChunk...)
+		case equalLines(upstreamChunk, baseChunk), equalLines(localChunk, upstreamChunk):
+			out = append(out, localChunk...)
+		default:
+			conflicts++
+			out = append(out, conflictStartMarker)
+			out = append(out, localChunk...)
+			out = append(out, conflictSepMarker)
+			out = append(out, upstreamChunk...)
+			out = append(out, conflictEndMarker)
+		}
+
+		if next == -1 {
+			break
+		}
+		o, a, b = next, matchLocal[next], matchUpstream[next]
+	}
+
+	content := strings.Join(out, "\n")
+	if len(out) > 0 && (strings.HasSuffix(local, "\n") || strings.HasSuffix(upstream, "\n")) {
+		content += "\n"
+	}
+
+	return MergeResult{
+		Content:   content,
+		Conflicts: conflicts,
+	}
+}
+
+// DiffStats counts the lines added and removed when changing oldContent into newContent
+func DiffStats(oldContent, newContent string) (added int, removed int) {
+	oldLines := splitLines(oldContent)
+	newLines := splitLines(newContent)
+
+	matched := 0
+	for _, j := range matchLines(oldLines, newLines) {
+		if j >= 0 {
+			matched++
+		}
+	}
+	return len(newLines) - matched, len(oldLines) - matched
+}
+
+// splitLines splits content into lines, ignoring a single trailing newline
+func splitLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// equalLines reports whether two line slices are identical
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLines computes a longest common subsequence between a and b.
+// The result maps every index of a to its matching index in b, or -1 if unmatched.
+func matchLines(a, b []string) []int {
+	// lengths[i][j] holds the LCS length of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			matches[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"cursor++/internal/utils"
+)
+
+// ActionSkip marks a source file that is not an agent definition and is never installed
+const ActionSkip FileAction = "skip"
+
+// FilePlan describes what an install would do to a single file
+type FilePlan struct {
+	Path      string     // Path relative to the rules directory
+	Action    FileAction // Action the install would take
+	Added     int        // Lines that would be added to the project's copy
+	Removed   int        // Lines that would be removed from the project's copy
+	Conflicts int        // Conflicting hunks for ActionConflict
+}
+
+// InitPlan describes what Init would do to a project without changing anything
+type InitPlan struct {
+	Project  string     // Absolute path of the project
+	Sources  []string   // Names of the rule sources the files come from
+	Files    []FilePlan // Planned actions, agent definitions first, then skipped files
+	Register bool       // Whether the project would be added to the registry
+}
+
+// PlanInit computes the changes Init would make to the current directory without writing to it
+func (ai *AgentInitializer) PlanInit(opts InitOptions) (*InitPlan, error) {
+	ctx := context.Background()
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, wrapOpError("PlanInit", "cwd", err, "failed to get current directory")
+	}
+
+	resolved, files, cleanup, err := ai.resolveInstall(ctx, currentDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	manifest, err := LoadManifest(currentDir, ai.config)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &InitPlan{
+		Project:  currentDir,
+		Register: !ai.registry.HasProject(currentDir),
+	}
+
+	planned, err := ai.planSync(manifest, files, filepath.Join(currentDir, ai.config.RulesDirName))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range planned {
+		update := file.update
+		filePlan := FilePlan{
+			Path:      file.relPath,
+			Action:    update.change.Action,
+			Conflicts: update.change.Conflicts,
+		}
+		switch {
+		case update.content != nil:
+			filePlan.Added, filePlan.Removed = DiffStats(string(update.local), string(update.content))
+		case update.remove:
+			_, filePlan.Removed = DiffStats(string(update.local), "")
+		}
+		plan.Files = append(plan.Files, filePlan)
+	}
+
+	// Report files in the sources that are left out because they are not agent definitions
+	for _, rs := range resolved {
+		plan.Sources = append(plan.Sources, rs.source.Name)
+
+		skipped, err := utils.CollectNonMDCFiles(rs.dir)
+		if err != nil {
+			return nil, wrapOpError("PlanInit", rs.dir, err, "failed to list source files")
+		}
+		for _, relPath := range skipped {
+			plan.Files = append(plan.Files, FilePlan{Path: relPath, Action: ActionSkip})
+		}
+	}
+
+	return plan, nil
+}
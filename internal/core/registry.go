@@ -80,6 +80,36 @@ func (r *Registry) AddProject(projectPath string) error {
 	return r.save()
 }
 
+// RemoveProject removes a project from the registry.
+// It returns false if the project was not registered.
+func (r *Registry) RemoveProject(projectPath string) (bool, error) {
+	utils.Debug("Removing project from registry | project=" + projectPath)
+
+	for i, p := range r.Projects {
+		if p == projectPath {
+			r.Projects = append(r.Projects[:i], r.Projects[i+1:]...)
+			if err := r.save(); err != nil {
+				return false, wrapOpError("RemoveProject", r.path, err, "failed to save registry")
+			}
+			utils.Debug("Project removed from registry | project=" + projectPath)
+			return true, nil
+		}
+	}
+
+	utils.Debug("Project not registered, nothing to remove | project=" + projectPath)
+	return false, nil
+}
+
+// HasProject reports whether a project is registered
+func (r *Registry) HasProject(projectPath string) bool {
+	for _, p := range r.Projects {
+		if p == projectPath {
+			return true
+		}
+	}
+	return false
+}
+
 // GetProjects returns all registered projects
 func (r *Registry) GetProjects() []string {
 	utils.Debug("Getting registered projects | count=" + strconv.Itoa(len(r.Projects)))
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+
+	"cursor++/internal/utils"
+)
+
+// RemoveOptions controls how Remove backs a project out of cursor++
+type RemoveOptions struct {
+	Force bool // Also delete installed files that were edited locally
+}
+
+// RemoveResult records what Remove did to a project
+type RemoveResult struct {
+	Project      string   // Absolute path of the project
+	Removed      []string // Installed rule files that were deleted
+	Kept         []string // Installed rule files left in place because they were edited
+	Tracked      bool     // Whether the project had an install manifest
+	Unregistered bool     // Whether the project was removed from the registry
+}
+
+// Remove deletes the rule files cursor++ installed into the current directory, its
+// bookkeeping and lockfile, and unregisters the project. Rules authored by the user and
+// installed files edited locally are left alone unless opts.Force is set; while such
+// files are kept, so are the bookkeeping and lockfile.
+func (ai *AgentInitializer) Remove(opts RemoveOptions) (*RemoveResult, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, wrapOpError("Remove", "cwd", err, "failed to get current directory")
+	}
+
+	manifest, err := LoadManifest(currentDir, ai.config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RemoveResult{
+		Project: currentDir,
+		Tracked: utils.FileExists(manifest.path()),
+	}
+
+	targetDir := filepath.Join(currentDir, ai.config.RulesDirName)
+	for _, relPath := range manifest.TrackedFiles() {
+		targetPath := filepath.Join(targetDir, relPath)
+		update, err := ai.planRemoval(manifest, relPath, targetPath)
+		if err != nil {
+			return result, err
+		}
+
+		if update.change.Action == ActionDetach && !opts.Force {
+			utils.Debug("Keeping locally edited rule file | path=" + relPath)
+			result.Kept = append(result.Kept, relPath)
+			continue
+		}
+
+		if update.local != nil {
+			if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+				return result, wrapOpError("Remove", targetPath, err, "failed to remove rule file")
+			}
+			result.Removed = append(result.Removed, relPath)
+		}
+		removeEmptyParents(filepath.Dir(targetPath), targetDir)
+		if err := manifest.RemoveBase(relPath); err != nil {
+			return result, err
+		}
+	}
+
+	// Drop the bookkeeping and lockfile once nothing is tracked any more; otherwise keep
+	// tracking the edited files so a later forced remove can still find them
+	if len(result.Kept) == 0 {
+		stateDir := filepath.Join(currentDir, StateDirName)
+		if err := os.RemoveAll(stateDir); err != nil {
+			return result, wrapOpError("Remove", stateDir, err, "failed to remove state directory")
+		}
+		lockPath := filepath.Join(currentDir, LockFilePath)
+		if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+			return result, wrapOpError("Remove", lockPath, err, "failed to remove lockfile")
+		}
+	} else if result.Tracked {
+		if err := manifest.Save(); err != nil {
+			return result, err
+		}
+	}
+
+	// Leave no empty rules directory behind, but never touch one with user rules in it.
+	// The agents directory is created empty when listing agents after init.
+	removeEmptyParents(filepath.Join(targetDir, ai.config.AgentsDirName), targetDir)
+	removeEmptyParents(targetDir, filepath.Dir(targetDir))
+
+	result.Unregistered, err = ai.registry.RemoveProject(currentDir)
+	if err != nil {
+		return result, wrapOpError("Remove", currentDir, err, "failed to unregister project")
+	}
+
+	utils.Infof("Project removed | project=%s removed=%d kept=%d", currentDir, len(result.Removed), len(result.Kept))
+	return result, nil
+}
+
+// removeEmptyParents deletes dir and its parents while they are empty, stopping at stop
+func removeEmptyParents(dir, stop string) {
+	for dir != stop && len(dir) > len(stop) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
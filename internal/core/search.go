@@ -0,0 +1,369 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// Search filter fields
+const (
+	SearchFieldName     = "name"
+	SearchFieldTag      = "tag"
+	SearchFieldCategory = "category"
+)
+
+// Relevance weights of the places a search term is found in
+const (
+	searchWeightName        = 8.0
+	searchWeightDescription = 4.0
+	searchWeightTag         = 4.0
+	searchWeightBody        = 1.0
+)
+
+// snippetWidth is the number of characters shown around a match in a snippet line
+const snippetWidth = 120
+
+// SearchFilter restricts a search to agents whose field contains a value
+type SearchFilter struct {
+	Field string
+	Value string
+}
+
+// SearchQuery is a parsed search query: words and quoted phrases to rank by,
+// plus field filters such as name:git or tag:"code review"
+type SearchQuery struct {
+	Terms   []string
+	Filters []SearchFilter
+}
+
+// SearchMatch is a line of an agent file containing search terms
+type SearchMatch struct {
+	Line  int      `json:"line"`
+	Text  string   `json:"text"`  // The line, shortened around the first match if it is long
+	Spans [][2]int `json:"spans"` // Byte ranges of the matches in Text
+}
+
+// SearchResult is an agent matching a search, with its relevance score and best matching lines
+type SearchResult struct {
+	Agent   *agent.AgentDefinition `json:"-"`
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name"`
+	Score   float64                `json:"score"`
+	Matches []SearchMatch          `json:"matches"`
+}
+
+// ParseSearchQuery splits a query into terms, "quoted phrases" and field:value filters.
+// Matching is case-insensitive.
+func ParseSearchQuery(query string) (*SearchQuery, error) {
+	tokens, err := splitQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &SearchQuery{}
+	for _, token := range tokens {
+		field, value, found := strings.Cut(token.text, ":")
+		if found && !token.quoted {
+			switch strings.ToLower(field) {
+			case SearchFieldName, SearchFieldTag, SearchFieldCategory:
+				value = strings.Trim(value, `"`)
+				if value == "" {
+					return nil, wrapValidationError("query", fmt.Sprintf("%s: needs a value", field))
+				}
+				parsed.Filters = append(parsed.Filters, SearchFilter{Field: strings.ToLower(field), Value: value})
+				continue
+			}
+		}
+		parsed.Terms = append(parsed.Terms, token.text)
+	}
+
+	if len(parsed.Terms) == 0 && len(parsed.Filters) == 0 {
+		return nil, wrapValidationError("query", "empty search query")
+	}
+	return parsed, nil
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits a query at spaces outside of double quotes.
+// A token that is entirely quoted is a phrase; field:"a b" keeps its quotes for ParseSearchQuery.
+func splitQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	var current strings.Builder
+	inQuote := false
+
+	flush := func() {
+		text := current.String()
+		current.Reset()
+		quoted := len(text) >= 2 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`)
+		if quoted {
+			text = text[1 : len(text)-1]
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			tokens = append(tokens, queryToken{text: text, quoted: quoted})
+		}
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, wrapValidationError("query", "unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// SearchAgents searches the names, descriptions, tags and full content of the agents in a registry.
+// Every term must occur somewhere in an agent for it to match. Results are ranked by how often and
+// where the terms occur, weighting rare terms higher, and hold up to maxMatches matching lines each.
+func SearchAgents(registry *agent.Registry, query *SearchQuery, maxMatches int) ([]SearchResult, error) {
+	patterns := make([]*regexp.Regexp, len(query.Terms))
+	for i, term := range query.Terms {
+		patterns[i] = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+	}
+
+	type candidate struct {
+		definition *agent.AgentDefinition
+		content    string
+		hits       [][4]int // Per term: name, description, tag and body occurrences
+	}
+
+	var candidates []candidate
+	documentFrequency := make([]int, len(query.Terms))
+
+	for _, definition := range registry.ListAgents() {
+		if !matchesFilters(definition, query.Filters) {
+			continue
+		}
+
+		content, err := registry.Render(definition.ID)
+		if err != nil {
+			return nil, wrapOpError("SearchAgents", definition.ID, err, "failed to read agent")
+		}
+
+		c := candidate{definition: definition, content: content, hits: make([][4]int, len(patterns))}
+		matchesAll := true
+		for i, pattern := range patterns {
+			h := &c.hits[i]
+			// The name is usually derived from the ID, so a term found in both only counts once
+			h[0] = max(len(pattern.FindAllStringIndex(definition.Name, -1)), len(pattern.FindAllStringIndex(definition.ID, -1)))
+			h[1] = len(pattern.FindAllStringIndex(definition.Description, -1))
+			for _, tag := range definition.Tags {
+				if pattern.MatchString(tag) {
+					h[2]++
+				}
+			}
+			h[3] = len(pattern.FindAllStringIndex(content, -1))
+
+			if h[0]+h[1]+h[2]+h[3] == 0 {
+				matchesAll = false
+				break
+			}
+		}
+		if !matchesAll {
+			continue
+		}
+
+		for i := range patterns {
+			documentFrequency[i]++
+		}
+		candidates = append(candidates, c)
+	}
+
+	total := float64(len(registry.ListAgents()))
+	results := make([]SearchResult, 0, len(candidates))
+	for _, c := range candidates {
+		score := 0.0
+		for i, h := range c.hits {
+			idf := math.Log(1 + total/float64(documentFrequency[i]))
+			score += idf * (searchWeightName*saturate(h[0]) + searchWeightDescription*saturate(h[1]) +
+				searchWeightTag*saturate(h[2]) + searchWeightBody*saturate(h[3]))
+		}
+
+		results = append(results, SearchResult{
+			Agent:   c.definition,
+			ID:      c.definition.ID,
+			Name:    c.definition.Name,
+			Score:   math.Round(score*100) / 100,
+			Matches: matchingLines(c.content, patterns, maxMatches),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	utils.Debugf("Searched agents | terms=%d filters=%d results=%d", len(query.Terms), len(query.Filters), len(results))
+	return results, nil
+}
+
+// matchesFilters reports whether an agent passes every field filter
+func matchesFilters(definition *agent.AgentDefinition, filters []SearchFilter) bool {
+	for _, filter := range filters {
+		value := strings.ToLower(filter.Value)
+		switch filter.Field {
+		case SearchFieldName:
+			if !strings.Contains(strings.ToLower(definition.Name), value) && !strings.Contains(strings.ToLower(definition.ID), value) {
+				return false
+			}
+		case SearchFieldTag:
+			found := false
+			for _, tag := range definition.Tags {
+				if strings.EqualFold(tag, filter.Value) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		case SearchFieldCategory:
+			if !strings.Contains(strings.ToLower(ui.DetectAgentCategory(definition)), value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// saturate dampens repeated occurrences, so one term mentioned often does not outweigh the others
+func saturate(count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return 1 + math.Log(float64(count))
+}
+
+// matchingLines returns up to max lines of content containing the patterns, preferring
+// lines that contain more of them, in file order
+func matchingLines(content string, patterns []*regexp.Regexp, max int) []SearchMatch {
+	if len(patterns) == 0 || max <= 0 {
+		return []SearchMatch{}
+	}
+
+	type scored struct {
+		match    SearchMatch
+		distinct int
+	}
+	var lines []scored
+	for i, line := range strings.Split(content, "\n") {
+		var spans [][2]int
+		distinct := 0
+		for _, pattern := range patterns {
+			found := pattern.FindAllStringIndex(line, -1)
+			if len(found) > 0 {
+				distinct++
+			}
+			for _, span := range found {
+				spans = append(spans, [2]int{span[0], span[1]})
+			}
+		}
+		if distinct == 0 {
+			continue
+		}
+		text, spans := snippet(strings.TrimRight(line, " \t\r"), spans)
+		lines = append(lines, scored{match: SearchMatch{Line: i + 1, Text: text, Spans: spans}, distinct: distinct})
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].distinct > lines[j].distinct
+	})
+	if len(lines) > max {
+		lines = lines[:max]
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].match.Line < lines[j].match.Line
+	})
+
+	matches := make([]SearchMatch, 0, len(lines))
+	for _, l := range lines {
+		matches = append(matches, l.match)
+	}
+	return matches
+}
+
+// snippet shortens a line to about snippetWidth characters around its first match,
+// returning the shortened line with its match spans merged and shifted to fit
+func snippet(line string, spans [][2]int) (string, [][2]int) {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var merged [][2]int
+	for _, span := range spans {
+		if n := len(merged); n > 0 && span[0] <= merged[n-1][1] {
+			if span[1] > merged[n-1][1] {
+				merged[n-1][1] = span[1]
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	// Leading indentation carries no information in a snippet
+	trimmed := strings.TrimLeft(line, " \t")
+	offset := len(line) - len(trimmed)
+	start, end := offset, len(line)
+
+	if len(trimmed) > snippetWidth {
+		start = merged[0][0] - snippetWidth/3
+		if start < offset {
+			start = offset
+		}
+		end = start + snippetWidth
+		if end > len(line) {
+			end = len(line)
+		}
+		// Do not cut through multi-byte characters
+		for start > offset && !utf8.RuneStart(line[start]) {
+			start--
+		}
+		for end < len(line) && !utf8.RuneStart(line[end]) {
+			end++
+		}
+	}
+
+	prefix, suffix := "", ""
+	if start > offset {
+		prefix = "…"
+	}
+	if end < len(line) {
+		suffix = "…"
+	}
+
+	var shifted [][2]int
+	for _, span := range merged {
+		if span[1] <= start || span[0] >= end {
+			continue
+		}
+		s, e := span[0], span[1]
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		shifted = append(shifted, [2]int{s - start + len(prefix), e - start + len(prefix)})
+	}
+	return prefix + line[start:end] + suffix, shifted
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+// Selection restricts which agents are installed into a project
+type Selection struct {
+	Agents     []string `json:"agents,omitempty"`     // Agent IDs to install; all agents if empty
+	Exclude    []string `json:"exclude,omitempty"`    // Agent IDs never to install
+	Categories []string `json:"categories,omitempty"` // Categories to install in addition to Agents
+	Tags       []string `json:"tags,omitempty"`       // Tags of agents to install in addition to Agents
+}
+
+// IsEmpty reports whether the selection installs every agent
+func (s *Selection) IsEmpty() bool {
+	return s == nil || (len(s.Agents) == 0 && len(s.Exclude) == 0 && len(s.Categories) == 0 && len(s.Tags) == 0)
+}
+
+// String describes the selection for display
+func (s *Selection) String() string {
+	if s.IsEmpty() {
+		return "all agents"
+	}
+
+	var parts []string
+	if len(s.Agents) > 0 {
+		parts = append(parts, "agents: "+strings.Join(s.Agents, ", "))
+	}
+	if len(s.Categories) > 0 {
+		parts = append(parts, "categories: "+strings.Join(s.Categories, ", "))
+	}
+	if len(s.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(s.Tags, ", "))
+	}
+	if len(s.Exclude) > 0 {
+		parts = append(parts, "excluding: "+strings.Join(s.Exclude, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// effectiveSelection returns the selection to use for a project: override if given,
+// otherwise the selection remembered in the project's manifest
+func (ai *AgentInitializer) effectiveSelection(projectDir string, override *Selection) (*Selection, error) {
+	if override != nil {
+		return override, nil
+	}
+
+	manifest, err := LoadManifest(projectDir, ai.config)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Selection, nil
+}
+
+// ExcludeAgent stops an agent from being installed into the current project again.
+// If cursor++ installed the agent, its file is no longer tracked and the agent is added
+// to the project's remembered exclusions and dropped from the lockfile.
+// It reports whether the agent had been installed by cursor++.
+func (ai *AgentInitializer) ExcludeAgent(id string) (bool, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return false, wrapOpError("ExcludeAgent", "cwd", err, "failed to get current directory")
+	}
+
+	manifest, err := LoadManifest(currentDir, ai.config)
+	if err != nil {
+		return false, err
+	}
+
+	var tracked []string
+	for _, relPath := range manifest.TrackedFiles() {
+		if strings.TrimSuffix(filepath.Base(relPath), ".mdc") == id {
+			tracked = append(tracked, relPath)
+		}
+	}
+	if len(tracked) == 0 {
+		return false, nil
+	}
+
+	for _, relPath := range tracked {
+		if err := manifest.RemoveBase(relPath); err != nil {
+			return true, err
+		}
+	}
+	if manifest.Selection == nil {
+		manifest.Selection = &Selection{}
+	}
+	if !toSet(manifest.Selection.Exclude)[id] {
+		manifest.Selection.Exclude = append(manifest.Selection.Exclude, id)
+	}
+	if err := manifest.Save(); err != nil {
+		return true, err
+	}
+
+	// Keep init --locked working: the lockfile must list exactly the selected files
+	lock, err := LoadLockFile(currentDir)
+	if err != nil {
+		utils.Debug("No lockfile to update | error=" + err.Error())
+		return true, nil
+	}
+	for _, relPath := range tracked {
+		delete(lock.Files, relPath)
+	}
+	if err := lock.Save(currentDir, ai.config); err != nil {
+		return true, err
+	}
+
+	utils.Infof("Agent excluded from project | id=%s project=%s", id, currentDir)
+	return true, nil
+}
+
+// applySelection filters the files to install down to the selected agents.
+// files maps paths relative to the rules directory to the upstream file.
+func applySelection(files map[string]string, selection *Selection) map[string]string {
+	if selection.IsEmpty() {
+		return files
+	}
+
+	include := len(selection.Agents) == 0 && len(selection.Categories) == 0 && len(selection.Tags) == 0
+	wanted := toSet(selection.Agents)
+	excluded := toSet(selection.Exclude)
+	seen := make(map[string]bool)
+
+	selected := make(map[string]string, len(files))
+	for relPath, path := range files {
+		id := strings.TrimSuffix(filepath.Base(relPath), ".mdc")
+		seen[id] = true
+
+		if excluded[id] {
+			continue
+		}
+		if include || wanted[id] || matchesMetadata(path, selection) {
+			selected[relPath] = path
+		}
+	}
+
+	// Unknown IDs are usually typos, but may also be agents removed upstream
+	for _, id := range append(append([]string(nil), selection.Agents...), selection.Exclude...) {
+		if !seen[id] {
+			utils.Warn("Selected agent not found in rule sources | id=" + id)
+			ui.Warning("Agent '%s' was not found in any rule source", id)
+		}
+	}
+
+	utils.Debugf("Applied agent selection | selection=%s available=%d selected=%d",
+		selection.String(), len(files), len(selected))
+	return selected
+}
+
+// matchesMetadata reports whether the agent defined in path belongs to one of the selected
+// categories or has one of the selected tags
+func matchesMetadata(path string, selection *Selection) bool {
+	if len(selection.Categories) == 0 && len(selection.Tags) == 0 {
+		return false
+	}
+
+	def, err := agent.LoadDefinition(path)
+	if err != nil {
+		return false
+	}
+	return matchesCategory(def, selection.Categories) || matchesTag(def, selection.Tags)
+}
+
+// matchesCategory reports whether an agent belongs to one of categories.
+// Categories match case-insensitively on any part of their letters and digits, so
+// "testing", "git" and "review" match "Testing", "Git & Version Control" and "Code Review".
+func matchesCategory(def *agent.AgentDefinition, categories []string) bool {
+	category := normalizeCategory(ui.DetectAgentCategory(def))
+	for _, want := range categories {
+		if want := normalizeCategory(want); want != "" && strings.Contains(category, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTag reports whether an agent declares one of tags. Tags match case-insensitively but
+// in full, as in the tag: filter of agent search.
+func matchesTag(def *agent.AgentDefinition, tags []string) bool {
+	for _, tag := range def.Tags {
+		for _, want := range tags {
+			if strings.EqualFold(tag, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeCategory lowercases a category name and drops everything but letters and digits
+func normalizeCategory(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// toSet converts a list of strings into a set
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// sortedKeys returns the keys of a file map in sorted order
+func sortedKeys(files map[string]string) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
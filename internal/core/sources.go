@@ -0,0 +1,342 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor++/internal/git"
+	"cursor++/internal/ui"
+	"cursor++/internal/utils"
+)
+
+const (
+	// sourcesDirName holds the clones of configured rule sources inside the data directory
+	sourcesDirName = "sources"
+
+	// fromSourceName names the one-off source given to init with --from
+	fromSourceName = "from"
+)
+
+// resolvedSource is a rule source that has been fetched and can be read from
+type resolvedSource struct {
+	source  utils.RuleSource
+	dir     string // Directory the agent definitions are read from
+	repoDir string // Git working copy, empty for local sources
+	commit  string // Checked out commit, empty for local sources
+}
+
+// sourceCacheDir returns where a git source is cloned to.
+// The default source keeps using the original agent path so existing caches stay valid.
+func (ai *AgentInitializer) sourceCacheDir(source utils.RuleSource) string {
+	if source.Name == utils.DefaultSourceName {
+		return ai.agentPath
+	}
+	return filepath.Join(ai.appPaths.DataDir, sourcesDirName, sanitizeFilename(source.Name))
+}
+
+// prepareSources makes every rule source available locally.
+// Missing clones are created; existing clones are only pulled when refresh is set.
+// The returned cleanup function must be called once the sources are no longer needed.
+func (ai *AgentInitializer) prepareSources(ctx context.Context, sources []utils.RuleSource, refresh bool, lock *LockFile) ([]*resolvedSource, func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+
+	resolved := make([]*resolvedSource, 0, len(sources))
+	for _, source := range sources {
+		var pinned string
+		if lock != nil {
+			pinned = lock.commitFor(source.Name)
+		}
+
+		rs, release, err := ai.prepareSource(ctx, source, refresh, pinned)
+		if release != nil {
+			cleanups = append(cleanups, release)
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, wrapOpError("prepareSources", source.Name, err, "failed to prepare rule source")
+		}
+		resolved = append(resolved, rs)
+	}
+
+	return resolved, cleanup, nil
+}
+
+// prepareSource makes a single rule source available locally, optionally pinned to a commit.
+// The returned function, if any, undoes temporary changes such as a pinned checkout or an extracted archive.
+func (ai *AgentInitializer) prepareSource(ctx context.Context, source utils.RuleSource, refresh bool, pinned string) (*resolvedSource, func(), error) {
+	rs := &resolvedSource{source: source}
+
+	if !source.IsGit() {
+		if utils.IsArchive(source.Path) {
+			root, cleanup, err := ai.extractArchiveSource(source.Path)
+			if err != nil {
+				return nil, nil, err
+			}
+			rs.dir = filepath.Join(root, source.Subfolder)
+			return rs, cleanup, nil
+		}
+
+		rs.dir = filepath.Join(source.Path, source.Subfolder)
+		if !utils.DirExists(rs.dir) {
+			return nil, nil, wrapNotFoundError("source directory", rs.dir)
+		}
+		return rs, nil, nil
+	}
+
+	rs.repoDir = ai.sourceCacheDir(source)
+	rs.dir = filepath.Join(rs.repoDir, source.Subfolder)
+
+	if ai.needsSetup(rs.repoDir, source.Subfolder) {
+		if !ai.handleInitialSetup(source, rs.repoDir) {
+			return nil, nil, wrapValidationError("setup", "agent system initialization cancelled")
+		}
+		if source.Ref != "" && pinned == "" {
+			if err := ai.gitMgr.CheckoutRef(ctx, rs.repoDir, source.Ref); err != nil {
+				return nil, nil, err
+			}
+		}
+	} else if refresh && pinned == "" {
+		ui.Info("Refreshing agent definitions from %s...", source.URL)
+		if err := ai.migrateCache(ctx, source, rs.repoDir); err != nil {
+			return nil, nil, err
+		}
+		if err := ai.gitMgr.Refresh(ctx, source.URL, rs.repoDir, source.Ref); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var restore func()
+	if pinned != "" {
+		ui.Info("Using locked commit %s for source '%s'", pinned, source.Name)
+		previousBranch, err := ai.gitMgr.CheckoutCommit(ctx, rs.repoDir, source.URL, pinned, ai.cloneOptions(source))
+		if previousBranch != "" {
+			restore = func() { ai.restoreBranch(ctx, rs.repoDir, previousBranch) }
+		}
+		if err != nil {
+			return nil, restore, err
+		}
+	}
+
+	commit, err := ai.gitMgr.HeadCommit(ctx, rs.repoDir)
+	if err != nil {
+		utils.Warn("Could not resolve source commit | source=" + source.Name + ", error=" + err.Error())
+	}
+	rs.commit = commit
+
+	return rs, restore, nil
+}
+
+// cloneOptions returns how a git source is cloned according to the configuration.
+// Local file:// repositories are always cloned in full: there is nothing to save and
+// the built-in go-git transport cannot serve shallow clones.
+func (ai *AgentInitializer) cloneOptions(source utils.RuleSource) git.CloneOptions {
+	var opts git.CloneOptions
+	if !strings.HasPrefix(source.URL, "file://") && ai.config.CloneDepth > 0 {
+		opts.Depth = ai.config.CloneDepth
+	}
+	if ai.config.SparseCheckout && source.Subfolder != "" {
+		opts.SparsePaths = []string{source.Subfolder}
+	}
+	return opts
+}
+
+// migrateCache converts an existing source cache to the configured clone depth,
+// so caches created before shallow clones were supported shrink on the next refresh
+func (ai *AgentInitializer) migrateCache(ctx context.Context, source utils.RuleSource, repoDir string) error {
+	opts := ai.cloneOptions(source)
+	migrated, err := ai.gitMgr.EnsureCloneMode(ctx, source.URL, repoDir, opts)
+	if err != nil {
+		return wrapOpError("migrateCache", repoDir, err, "failed to convert source cache")
+	}
+	if !migrated {
+		return nil
+	}
+
+	if opts.Depth > 0 {
+		ui.Info("Converted cache of source '%s' to a shallow clone", source.Name)
+	} else {
+		ui.Info("Fetched the full history of source '%s'", source.Name)
+	}
+	return nil
+}
+
+// prepareFromSource makes a one-off source available without touching the network.
+// from may be a local directory, a file:// git URL, or a .tar.gz/.zip archive.
+// The returned cleanup function removes any temporary copies.
+func (ai *AgentInitializer) prepareFromSource(ctx context.Context, from string) (*resolvedSource, func(), error) {
+	noop := func() {}
+
+	if strings.Contains(from, "://") {
+		if !strings.HasPrefix(from, "file://") {
+			return nil, nil, wrapValidationError("from", "only local directories, file:// URLs and .tar.gz/.zip archives are supported")
+		}
+
+		tempDir, err := os.MkdirTemp("", "cursor++-from-")
+		if err != nil {
+			return nil, nil, wrapOpError("prepareFromSource", from, err, "failed to create temporary directory")
+		}
+		cleanup := func() { os.RemoveAll(tempDir) }
+
+		repoDir := filepath.Join(tempDir, "repo")
+		ui.Info("Cloning local repository %s...", from)
+		if err := ai.gitMgr.CloneOrPull(ctx, from, repoDir, git.CloneOptions{}); err != nil {
+			cleanup()
+			return nil, nil, wrapOpError("prepareFromSource", from, err, "failed to clone local repository")
+		}
+
+		commit, err := ai.gitMgr.HeadCommit(ctx, repoDir)
+		if err != nil {
+			utils.Warn("Could not resolve source commit | source=" + from + ", error=" + err.Error())
+		}
+
+		source := utils.RuleSource{Name: fromSourceName, URL: from, Subfolder: ai.detectSubfolder(repoDir)}
+		return &resolvedSource{
+			source:  source,
+			dir:     filepath.Join(repoDir, source.Subfolder),
+			repoDir: repoDir,
+			commit:  commit,
+		}, cleanup, nil
+	}
+
+	absPath, err := filepath.Abs(from)
+	if err != nil {
+		return nil, nil, wrapOpError("prepareFromSource", from, err, "failed to resolve path")
+	}
+
+	if utils.IsArchive(absPath) {
+		root, cleanup, err := ai.extractArchiveSource(absPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		source := utils.RuleSource{Name: fromSourceName, Path: absPath, Subfolder: ai.detectSubfolder(root)}
+		return &resolvedSource{
+			source: source,
+			dir:    filepath.Join(root, source.Subfolder),
+		}, cleanup, nil
+	}
+
+	if !utils.DirExists(absPath) {
+		return nil, nil, wrapNotFoundError("source directory", absPath)
+	}
+
+	source := utils.RuleSource{Name: fromSourceName, Path: absPath, Subfolder: ai.detectSubfolder(absPath)}
+	return &resolvedSource{
+		source: source,
+		dir:    filepath.Join(absPath, source.Subfolder),
+	}, noop, nil
+}
+
+// extractArchiveSource unpacks an archive of agent definitions into a temporary directory
+// and returns the directory holding its contents together with a function removing it
+func (ai *AgentInitializer) extractArchiveSource(archivePath string) (string, func(), error) {
+	if !utils.FileExists(archivePath) {
+		return "", nil, wrapNotFoundError("archive", archivePath)
+	}
+
+	tempDir, err := os.MkdirTemp("", "cursor++-from-")
+	if err != nil {
+		return "", nil, wrapOpError("extractArchiveSource", archivePath, err, "failed to create temporary directory")
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	ui.Info("Extracting agent definitions from %s...", archivePath)
+	if err := utils.ExtractArchive(archivePath, tempDir, ai.config.DirPermission, ai.config.FilePermission); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return utils.ArchiveRoot(tempDir), cleanup, nil
+}
+
+// detectSubfolder returns the configured source folder if root contains it with agent
+// definitions, so a full copy of a rule repository can be used as well as a bare rules folder
+func (ai *AgentInitializer) detectSubfolder(root string) string {
+	if ai.config.SourceFolder == "" {
+		return ""
+	}
+	hasMDCFiles, err := utils.HasMDCFiles(filepath.Join(root, ai.config.SourceFolder))
+	if err != nil || !hasMDCFiles {
+		return ""
+	}
+	return ai.config.SourceFolder
+}
+
+// needsSetup reports whether a git source cache is missing or holds no agent definitions
+func (ai *AgentInitializer) needsSetup(repoDir, subfolder string) bool {
+	if !utils.DirExists(repoDir) {
+		utils.Debug("Agent location does not exist | path=" + repoDir)
+		ui.Warning("Agent location does not exist: %s", repoDir)
+		return true
+	}
+
+	sourceFolderPath := filepath.Join(repoDir, subfolder)
+	if !utils.DirExists(sourceFolderPath) {
+		utils.Debug("Source folder does not exist | path=" + sourceFolderPath)
+		ui.Warning("Source folder does not exist: %s", sourceFolderPath)
+		return true
+	}
+
+	hasMDCFiles, err := utils.HasMDCFiles(sourceFolderPath)
+	if err != nil || !hasMDCFiles {
+		utils.Debug("Source folder contains no definitions | path=" + sourceFolderPath)
+		ui.Warning("Source folder exists but contains no definitions: %s", sourceFolderPath)
+		return true
+	}
+
+	if utils.IsVerbose() {
+		utils.Info("Found existing agent definitions in " + sourceFolderPath)
+	}
+	return false
+}
+
+// overlaySources merges the agent definitions of all sources into a single set of files.
+// Sources are expected in ascending priority, so later sources win on agent ID clashes.
+// Two files with the same agent ID in one source are an error, as neither can be preferred.
+// The result maps the path relative to the rules directory to the file to install.
+func overlaySources(sources []*resolvedSource) (map[string]string, error) {
+	byID := make(map[string]string)   // agent ID -> relative path
+	files := make(map[string]string)  // relative path -> absolute path
+	owners := make(map[string]string) // agent ID -> source name
+
+	for _, rs := range sources {
+		sourceFiles, err := utils.CollectMDCFiles(rs.dir)
+		if err != nil {
+			return nil, wrapOpError("overlaySources", rs.dir, err, "failed to list agent definitions")
+		}
+
+		inSource := make(map[string]string, len(sourceFiles)) // agent ID -> relative path
+		for _, relPath := range sortedKeys(sourceFiles) {
+			id := strings.TrimSuffix(filepath.Base(relPath), ".mdc")
+			if other, exists := inSource[id]; exists {
+				return nil, wrapValidationError("sources", fmt.Sprintf("source %q defines agent %s twice, in %s and %s",
+					rs.source.Name, id, other, relPath))
+			}
+			inSource[id] = relPath
+
+			if previous, exists := byID[id]; exists {
+				utils.Debugf("Agent overridden by higher priority source | id=%s source=%s previous=%s",
+					id, rs.source.Name, owners[id])
+				delete(files, previous)
+			}
+			byID[id] = relPath
+			owners[id] = rs.source.Name
+			files[relPath] = sourceFiles[relPath]
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, wrapValidationError("sources", "no agent definitions found in any rule source")
+	}
+
+	utils.Debug(fmt.Sprintf("Overlaid rule sources | sources=%d files=%d", len(sources), len(files)))
+	return files, nil
+}
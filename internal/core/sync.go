@@ -65,6 +65,13 @@ func NewAgentInitializer() (*AgentInitializer, error) {
 		return nil, wrapOpError("NewAgentInitializer", agentPath, err, "failed to create agent directory")
 	}
 
+	// Pick the git implementation; go-git works where no git binary is installed
+	gitService, err := git.NewService(config.GitBackend)
+	if err != nil {
+		return nil, wrapOpError("NewAgentInitializer", "gitBackend", err, "invalid git backend")
+	}
+	utils.Debugf("Using git backend | backend=%s", gitServiceName(gitService))
+
 	// Load or create registry
 	registry, err := LoadRegistry(registryPath, config)
 	if err != nil {
@@ -77,12 +84,31 @@ func NewAgentInitializer() (*AgentInitializer, error) {
 		registry:  registry,
 		config:    config,
 		appPaths:  appPaths,
-		gitMgr:    git.NewGitManager(nil),
+		gitMgr:    git.NewGitManager(gitService),
 	}, nil
 }
 
+// gitServiceName describes a git service implementation for logging
+func gitServiceName(service git.GitService) string {
+	if _, ok := service.(*git.GoGitService); ok {
+		return git.BackendGoGit
+	}
+	return git.BackendCommand
+}
+
+// InitOptions controls how Init installs agent definitions
+type InitOptions struct {
+	Locked bool   // Install exactly the commit pinned by the project's lockfile
+	From   string // Install from a local directory, file:// URL or archive instead of the configured sources
+
+	// Selection replaces the agents remembered for the project; nil keeps the remembered selection
+	Selection *Selection
+}
+
 // Init initializes the agent system in the current directory
-func (ai *AgentInitializer) Init() error {
+func (ai *AgentInitializer) Init(opts InitOptions) error {
+	ctx := context.Background()
+
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return wrapOpError("Init", "cwd", err, "failed to get current directory")
@@ -106,82 +132,42 @@ func (ai *AgentInitializer) Init() error {
 	targetPath := filepath.Join(currentDir, ai.config.RulesDirName)
 	utils.Debug("Init target path | path=" + targetPath)
 
-	// Check if the agent location exists and has valid definitions
-	needsSetup := false
-
-	if !utils.DirExists(ai.agentPath) {
-		utils.Debug("Agent location does not exist | path=" + ai.agentPath)
-		ui.Warning("Agent location does not exist: %s", ai.agentPath)
-		needsSetup = true
-	} else {
-		// Check source folder if it's specified
-		if ai.config.SourceFolder != "" {
-			sourceFolderPath := filepath.Join(ai.agentPath, ai.config.SourceFolder)
-			if !utils.DirExists(sourceFolderPath) {
-				utils.Debug("Source folder does not exist | path=" + sourceFolderPath)
-				ui.Warning("Source folder does not exist: %s", sourceFolderPath)
-				needsSetup = true
-			} else {
-				hasMDCFiles, err := utils.HasMDCFiles(sourceFolderPath)
-				if err != nil {
-					return wrapOpError("Init", sourceFolderPath, err, "failed to check for agent definitions in source folder")
-				}
-
-				if !hasMDCFiles {
-					utils.Debug("Source folder exists but contains no definitions | path=" + sourceFolderPath)
-					ui.Warning("Source folder exists but contains no definitions: %s", sourceFolderPath)
-					needsSetup = true
-				} else if utils.IsVerbose() {
-					utils.Info("Found existing agent definitions in source folder")
-				}
-			}
-		} else {
-			hasMDCFiles, err := utils.HasMDCFiles(ai.agentPath)
-			if err != nil {
-				return wrapOpError("Init", ai.agentPath, err, "failed to check for agent definitions")
-			}
-
-			if !hasMDCFiles {
-				utils.Debug("Agent location exists but contains no definitions | path=" + ai.agentPath)
-				ui.Warning("Agent location exists but contains no definitions: %s", ai.agentPath)
-				needsSetup = true
-			} else if utils.IsVerbose() {
-				utils.Info("Found existing agent definitions")
-			}
-		}
-	}
-
-	if needsSetup {
-		if !ai.handleInitialSetup() {
-			return wrapValidationError("setup", "agent system initialization cancelled")
-		}
+	resolved, files, cleanup, err := ai.resolveInstall(ctx, currentDir, opts)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
 	// Log copy operation details
 	if utils.IsVerbose() {
-		if ai.config.SourceFolder != "" {
-			utils.Infof("Copying agent definitions from '%s' subfolder to project directory: %s",
-				ai.config.SourceFolder, targetPath)
-		} else {
-			utils.Infof("Copying all agent definitions to project directory: %s", targetPath)
+		for _, rs := range resolved {
+			utils.Infof("Using rule source '%s' (priority %d) from %s", rs.source.Name, rs.source.Priority, rs.dir)
 		}
+		utils.Infof("Copying %d agent definitions to project directory: %s", len(files), targetPath)
 	}
 
 	// Debug with more details about the copy operation
 	if utils.IsDebug() {
-		utils.Debugf("Copy operation details | source=%s | sourceFolder=%s | target=%s | permission=%o",
-			ai.agentPath, ai.config.SourceFolder, targetPath, ai.config.DirPermission)
+		utils.Debugf("Copy operation details | sources=%d | files=%d | target=%s | permission=%o",
+			len(resolved), len(files), targetPath, ai.config.DirPermission)
 	}
 
-	// Ensure the target directory exists
-	if err := os.MkdirAll(targetPath, ai.config.DirPermission); err != nil {
-		return wrapOpError("Init", targetPath, err, "failed to create target directory")
-	}
-
-	// Copy agent definitions selectively to the rules directory
-	if err := utils.CopyDirSelective(ai.agentPath, targetPath, ai.config.SourceFolder); err != nil {
+	// Install agent definitions, merging with any local edits
+	changes, err := ai.syncRules(files, currentDir, opts.Selection)
+	if err != nil {
 		return wrapOpError("Init", targetPath, err, "failed to copy agent definitions")
 	}
+	reportFileChanges(changes)
+
+	// Pin the installed commits so teammates can reproduce this install with --locked
+	if !opts.Locked {
+		if err := ai.writeLockFile(currentDir, resolved, changes); err != nil {
+			utils.Warn("Failed to write lockfile: " + err.Error())
+			ui.Warning("Could not write %s: %v", LockFilePath, err)
+		} else if utils.IsVerbose() {
+			utils.Info("Lockfile written to " + LockFilePath)
+		}
+	}
 
 	if utils.IsDebug() {
 		// Verify the copy operation
@@ -213,78 +199,139 @@ func (ai *AgentInitializer) Init() error {
 	return nil
 }
 
-// handleInitialSetup manages the initial setup of the agent system
-func (ai *AgentInitializer) handleInitialSetup() bool {
-	// Default repository URL instead of prompting the user
-	defaultRepoURL := "https://github.com/nsnarender5511/AgenticSystem"
+// resolveInstall prepares the rule sources for an install into projectDir and overlays them.
+// The returned cleanup function must be called once the files are no longer needed.
+func (ai *AgentInitializer) resolveInstall(ctx context.Context, projectDir string, opts InitOptions) ([]*resolvedSource, map[string]string, func(), error) {
+	// In locked mode, install from the pinned sources and commits instead of the configuration
+	sources := ai.config.GetSources()
+	var lock *LockFile
+	if opts.Locked {
+		var err error
+		lock, err = LoadLockFile(projectDir)
+		if err != nil {
+			return nil, nil, nil, wrapOpError("Init", LockFilePath, err, "cannot install in locked mode")
+		}
+		sources = lock.RuleSources()
+	}
 
-	ui.Info("\nNo agent definitions found. Automatically cloning from default repository...")
-	ui.Info("Repository URL: %s", defaultRepoURL)
+	var resolved []*resolvedSource
+	var cleanup func()
+	var err error
+	if opts.From != "" {
+		// An explicit source replaces the configured ones and never touches the network
+		var rs *resolvedSource
+		rs, cleanup, err = ai.prepareFromSource(ctx, opts.From)
+		if err != nil {
+			return nil, nil, nil, wrapOpError("Init", opts.From, err, "failed to read agent definitions")
+		}
+		resolved = []*resolvedSource{rs}
+	} else {
+		resolved, cleanup, err = ai.prepareSources(ctx, sources, false, lock)
+		if err != nil {
+			return nil, nil, nil, wrapOpError("Init", "sources", err, "failed to prepare rule sources")
+		}
+	}
+
+	// Overlay the sources so higher priorities replace agents with the same ID
+	files, err := overlaySources(resolved)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, wrapOpError("Init", "sources", err, "failed to collect agent definitions")
+	}
+
+	// Render agents that extend others before the selection, which may leave out their parents
+	release, err := ai.renderExtendedAgents(files)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, wrapOpError("Init", "sources", err, "failed to resolve agent inheritance")
+	}
+	sourcesCleanup := cleanup
+	cleanup = func() {
+		release()
+		sourcesCleanup()
+	}
+
+	// Only install the agents selected for the project
+	selection, err := ai.effectiveSelection(projectDir, opts.Selection)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	files = applySelection(files, selection)
+	if !selection.IsEmpty() {
+		ui.Info("Installing selected agents (%s)", selection)
+	}
+
+	if lock != nil {
+		if err := lock.Verify(files); err != nil {
+			cleanup()
+			return nil, nil, nil, wrapOpError("Init", LockFilePath, err, "locked install verification failed")
+		}
+	}
+
+	return resolved, files, cleanup, nil
+}
+
+// handleInitialSetup clones a git rule source into its cache directory
+func (ai *AgentInitializer) handleInitialSetup(source utils.RuleSource, repoDir string) bool {
+	if source.Name == utils.DefaultSourceName {
+		ui.Info("\nNo agent definitions found. Automatically cloning from default repository...")
+	} else {
+		ui.Info("\nCloning rule source '%s'...", source.Name)
+	}
+	ui.Info("Repository URL: %s", source.URL)
 
 	// Add more verbose information
 	if utils.IsVerbose() {
-		utils.Infof("Target agent path: %s", ai.agentPath)
-		if ai.config.SourceFolder != "" {
-			utils.Infof("Will use '%s' subfolder for rules", ai.config.SourceFolder)
+		utils.Infof("Target agent path: %s", repoDir)
+		if source.Subfolder != "" {
+			utils.Infof("Will use '%s' subfolder for rules", source.Subfolder)
 		}
 	}
 
 	// Add detailed debug information
 	if utils.IsDebug() {
 		utils.Debugf("Clone operation details | repo=%s | path=%s | permission=%o | sourceFolder=%s",
-			defaultRepoURL, ai.agentPath, ai.config.DirPermission, ai.config.SourceFolder)
+			source.URL, repoDir, ai.config.DirPermission, source.Subfolder)
 		utils.Debugf("Agent registry details | projects=%d | registryPath=%s",
 			ai.registry.GetProjectCount(), ai.appPaths.GetRegistryFile(ai.config.RegistryFileName))
 	}
 
-	if err := ai.cloneRepository(defaultRepoURL); err != nil {
+	if err := ai.cloneRepository(source.URL, repoDir, ai.cloneOptions(source)); err != nil {
 		ui.Error(err.Error())
 		return false
 	}
 
 	// Verify and log the clone results
-	if utils.DirExists(ai.agentPath) {
-		// Check if source folder exists
-		if ai.config.SourceFolder != "" {
-			sourceFolderPath := filepath.Join(ai.agentPath, ai.config.SourceFolder)
-			if !utils.DirExists(sourceFolderPath) {
-				ui.Error("Source folder '%s' does not exist in the cloned repository", ai.config.SourceFolder)
-				return false
-			}
+	sourceFolderPath := filepath.Join(repoDir, source.Subfolder)
+	if !utils.DirExists(sourceFolderPath) {
+		ui.Error("Source folder '%s' does not exist in the cloned repository", source.Subfolder)
+		return false
+	}
 
-			// Verify it has MDC files
-			hasMDCFiles, _ := utils.HasMDCFiles(sourceFolderPath)
-			if !hasMDCFiles {
-				ui.Warning("Source folder '%s' exists but contains no agent definitions", ai.config.SourceFolder)
-			}
-		}
+	// Verify it has MDC files
+	hasMDCFiles, _ := utils.HasMDCFiles(sourceFolderPath)
+	if !hasMDCFiles {
+		ui.Warning("Source folder '%s' exists but contains no agent definitions", source.Subfolder)
+	}
 
-		if utils.IsVerbose() {
-			fileCount, _ := utils.CountFiles(ai.agentPath)
-			utils.Infof("Repository cloned successfully with %d files", fileCount)
+	if utils.IsVerbose() {
+		fileCount, _ := utils.CountFiles(repoDir)
+		utils.Infof("Repository cloned successfully with %d files", fileCount)
 
-			if ai.config.SourceFolder != "" {
-				sourceFolderPath := filepath.Join(ai.agentPath, ai.config.SourceFolder)
-				sourceFileCount, _ := utils.CountFiles(sourceFolderPath)
-				utils.Infof("Source folder '%s' contains %d files", ai.config.SourceFolder, sourceFileCount)
-			}
+		if source.Subfolder != "" {
+			sourceFileCount, _ := utils.CountFiles(sourceFolderPath)
+			utils.Infof("Source folder '%s' contains %d files", source.Subfolder, sourceFileCount)
 		}
+	}
 
-		if utils.IsDebug() {
-			if ai.config.SourceFolder != "" {
-				sourceFolderPath := filepath.Join(ai.agentPath, ai.config.SourceFolder)
-				mdcFiles, _ := utils.CountFilesByExt(sourceFolderPath, ".mdc")
-				utils.Debugf("Source folder definition details | path=%s | total files=%d | mdc files=%d",
-					sourceFolderPath, utils.CountFilesRecursive(sourceFolderPath), mdcFiles)
-			} else {
-				mdcFiles, _ := utils.CountFilesByExt(ai.agentPath, ".mdc")
-				utils.Debugf("Agent definition details | total files=%d | mdc files=%d",
-					utils.CountFilesRecursive(ai.agentPath), mdcFiles)
-			}
-		}
+	if utils.IsDebug() {
+		mdcFiles, _ := utils.CountFilesByExt(sourceFolderPath, ".mdc")
+		utils.Debugf("Source folder definition details | path=%s | total files=%d | mdc files=%d",
+			sourceFolderPath, utils.CountFilesRecursive(sourceFolderPath), mdcFiles)
 	}
 
-	ui.Success("Successfully cloned repository to %s", ai.agentPath)
+	ui.Success("Successfully cloned repository to %s", repoDir)
 	return true
 }
 
@@ -302,20 +349,27 @@ func (ai *AgentInitializer) createEmptyDirectory() error {
 	return nil
 }
 
-func (ai *AgentInitializer) cloneRepository(repoURL string) error {
-	ui.Info("Cloning repository %s to %s...", repoURL, ai.agentPath)
+func (ai *AgentInitializer) cloneRepository(repoURL, destDir string, opts git.CloneOptions) error {
+	ui.Info("Cloning repository %s to %s...", repoURL, destDir)
 
-	if err := os.MkdirAll(filepath.Dir(ai.agentPath), ai.config.DirPermission); err != nil {
-		return wrapOpError("cloneRepository", ai.agentPath, err, "failed to create parent directory")
+	if err := os.MkdirAll(filepath.Dir(destDir), ai.config.DirPermission); err != nil {
+		return wrapOpError("cloneRepository", destDir, err, "failed to create parent directory")
 	}
 
-	if err := ai.gitMgr.CloneOrPull(context.Background(), repoURL, ai.agentPath); err != nil {
+	if err := ai.gitMgr.CloneOrPull(context.Background(), repoURL, destDir, opts); err != nil {
 		return wrapOpError("cloneRepository", repoURL, err, "failed to clone repository")
 	}
 
 	return nil
 }
 
+// restoreBranch checks out the branch a source cache was on before a locked install
+func (ai *AgentInitializer) restoreBranch(ctx context.Context, repoDir, branch string) {
+	if err := ai.gitMgr.CheckoutRef(ctx, repoDir, branch); err != nil {
+		utils.Warn("Failed to restore cache branch | branch=" + branch + ", error=" + err.Error())
+	}
+}
+
 // GetRegistry returns the registry
 func (ai *AgentInitializer) GetRegistry() *Registry {
 	return ai.registry
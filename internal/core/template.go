@@ -0,0 +1,276 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"cursor++/internal/agent"
+	"cursor++/internal/utils"
+)
+
+// TemplateData is what agent templates are executed against
+type TemplateData struct {
+	Agent   *agent.AgentDefinition
+	Project ProjectFacts
+	Vars    map[string]string
+}
+
+// ProjectFacts describes the project a template is rendered for
+type ProjectFacts struct {
+	Name      string   // Base name of the project directory
+	Dir       string   // Absolute project directory
+	Branch    string   // Checked out git branch, empty outside a git repository
+	Languages []string // Languages detected from files such as go.mod or package.json
+	Date      string   // Today's date as YYYY-MM-DD
+}
+
+// languageMarkers maps files found in a project root to the language they indicate
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "Go"},
+	{"package.json", "JavaScript"},
+	{"tsconfig.json", "TypeScript"},
+	{"pyproject.toml", "Python"},
+	{"requirements.txt", "Python"},
+	{"setup.py", "Python"},
+	{"Cargo.toml", "Rust"},
+	{"pom.xml", "Java"},
+	{"build.gradle", "Java"},
+	{"build.gradle.kts", "Kotlin"},
+	{"Gemfile", "Ruby"},
+	{"composer.json", "PHP"},
+	{"Package.swift", "Swift"},
+}
+
+// templateFuncs are the functions available to agent templates besides the text/template builtins
+var templateFuncs = template.FuncMap{
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+}
+
+// LoadTemplate reads an agent template file. Variables lists every .Vars key the template uses
+// with its default value; Required lists the keys printed somewhere without a default.
+// Keys tested in if or with conditions are optional, as are their uses inside those blocks.
+func LoadTemplate(path string) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrapOpError("LoadTemplate", path, err, "failed to read template")
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, wrapParseError(path, err, 0)
+	}
+
+	scan := &variableScan{defaults: make(map[string]string), required: make(map[string]bool)}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			scan.walk(t.Tree.Root, false, nil)
+		}
+	}
+
+	result := &Template{
+		Content:   string(content),
+		Variables: scan.defaults,
+	}
+	for name := range scan.required {
+		result.Required = append(result.Required, name)
+	}
+	sort.Strings(result.Required)
+	result.IsRequired = len(result.Required) > 0
+	return result, nil
+}
+
+// MissingVariables returns the required variables of a template that vars does not set
+func (t *Template) MissingVariables(vars map[string]string) []string {
+	var missing []string
+	for _, name := range t.Required {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Execute renders the template. Variables without a value fall back to their defaults.
+func (t *Template) Execute(name string, data TemplateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(t.Content)
+	if err != nil {
+		return "", wrapParseError(name, err, 0)
+	}
+
+	vars := make(map[string]string, len(t.Variables)+len(data.Vars))
+	for key, value := range t.Variables {
+		vars[key] = value
+	}
+	for key, value := range data.Vars {
+		vars[key] = value
+	}
+	data.Vars = vars
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", wrapOpError("Execute", name, err, "failed to render template")
+	}
+	return out.String(), nil
+}
+
+// variableScan collects the .Vars keys used in a template parse tree
+type variableScan struct {
+	defaults map[string]string // Every key used, with its default value
+	required map[string]bool   // Keys used at least once without a default outside conditions
+}
+
+// walk visits a parse tree node. inCondition is set for the pipeline of if and with actions;
+// guarded holds the keys tested by the conditions enclosing the node.
+func (s *variableScan) walk(node parse.Node, inCondition bool, guarded map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			s.walk(child, false, guarded)
+		}
+	case *parse.ActionNode:
+		s.walk(n.Pipe, false, guarded)
+	case *parse.IfNode:
+		s.walkBranch(&n.BranchNode, true, guarded)
+	case *parse.WithNode:
+		s.walkBranch(&n.BranchNode, true, guarded)
+	case *parse.RangeNode:
+		s.walkBranch(&n.BranchNode, false, guarded)
+	case *parse.TemplateNode:
+		s.walk(n.Pipe, false, guarded)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		fallback, hasDefault := pipeDefault(n)
+		for _, cmd := range n.Cmds {
+			for _, name := range commandVariables(cmd) {
+				if _, seen := s.defaults[name]; !seen || hasDefault {
+					s.defaults[name] = fallback
+				}
+				if !hasDefault && !inCondition && !guarded[name] {
+					s.required[name] = true
+				}
+			}
+			for _, arg := range cmd.Args {
+				if pipe, ok := arg.(*parse.PipeNode); ok {
+					s.walk(pipe, inCondition, guarded)
+				}
+			}
+		}
+	}
+}
+
+// walkBranch visits an if, with or range action. Keys tested by a condition are
+// guarded in the branch taken when the condition holds.
+func (s *variableScan) walkBranch(n *parse.BranchNode, conditional bool, guarded map[string]bool) {
+	s.walk(n.Pipe, conditional, guarded)
+
+	inner := guarded
+	if conditional && n.Pipe != nil {
+		inner = make(map[string]bool, len(guarded))
+		for name := range guarded {
+			inner[name] = true
+		}
+		for _, cmd := range n.Pipe.Cmds {
+			for _, name := range commandVariables(cmd) {
+				inner[name] = true
+			}
+		}
+	}
+	s.walk(n.List, false, inner)
+	s.walk(n.ElseList, false, guarded)
+}
+
+// pipeDefault returns the fallback of a default call in a pipeline, if it has one
+func pipeDefault(pipe *parse.PipeNode) (string, bool) {
+	for _, cmd := range pipe.Cmds {
+		if len(cmd.Args) < 2 {
+			continue
+		}
+		if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "default" {
+			if str, ok := cmd.Args[1].(*parse.StringNode); ok {
+				return str.Text, true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// commandVariables returns the .Vars keys referenced directly by a command,
+// as .Vars.key, $.Vars.key or index .Vars "key"
+func commandVariables(cmd *parse.CommandNode) []string {
+	var names []string
+	for i, arg := range cmd.Args {
+		switch a := arg.(type) {
+		case *parse.FieldNode:
+			if len(a.Ident) >= 2 && a.Ident[0] == "Vars" {
+				names = append(names, a.Ident[1])
+			}
+		case *parse.VariableNode:
+			if len(a.Ident) >= 3 && a.Ident[0] == "$" && a.Ident[1] == "Vars" {
+				names = append(names, a.Ident[2])
+			}
+		case *parse.IdentifierNode:
+			if a.Ident != "index" || i+2 >= len(cmd.Args) {
+				continue
+			}
+			field, isField := cmd.Args[i+1].(*parse.FieldNode)
+			key, isString := cmd.Args[i+2].(*parse.StringNode)
+			if isField && isString && len(field.Ident) == 1 && field.Ident[0] == "Vars" {
+				names = append(names, key.Text)
+			}
+		}
+	}
+	return names
+}
+
+// ProjectFacts gathers the facts about a project directory that templates can use
+func (ai *AgentInitializer) ProjectFacts(dir string) ProjectFacts {
+	facts := ProjectFacts{
+		Name: filepath.Base(dir),
+		Dir:  dir,
+		Date: time.Now().Format("2006-01-02"),
+	}
+
+	seen := make(map[string]bool)
+	for _, marker := range languageMarkers {
+		if utils.FileExists(filepath.Join(dir, marker.file)) && !seen[marker.language] {
+			seen[marker.language] = true
+			facts.Languages = append(facts.Languages, marker.language)
+		}
+	}
+
+	if utils.DirExists(filepath.Join(dir, ".git")) {
+		branch, err := ai.gitMgr.CurrentBranch(context.Background(), dir)
+		if err != nil {
+			utils.Debug("Cannot determine project branch | dir=" + dir + ", error=" + err.Error())
+		}
+		facts.Branch = branch
+	}
+
+	return facts
+}
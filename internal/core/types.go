@@ -26,6 +26,7 @@ type CursorRule struct {
 type Template struct {
 	Content    string            `json:"content"`
 	Variables  map[string]string `json:"variables"`
+	Required   []string          `json:"required,omitempty"` // Variables that must be given a value
 	IsRequired bool              `json:"is_required"`
 }
 
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"cursor++/internal/utils"
+)
+
+// UpdateStatus describes the outcome of re-syncing a single project
+type UpdateStatus string
+
+// Possible project update outcomes
+const (
+	UpdateStatusUpdated   UpdateStatus = "updated"
+	UpdateStatusUnchanged UpdateStatus = "unchanged"
+	UpdateStatusSkipped   UpdateStatus = "skipped"
+	UpdateStatusFailed    UpdateStatus = "failed"
+)
+
+// ProjectUpdateResult records what happened to a registered project during update
+type ProjectUpdateResult struct {
+	Project string       // Absolute path of the project
+	Status  UpdateStatus // Outcome of the update
+	Changed int          // Number of rule files created or modified
+	Detail  string       // Human readable explanation of the outcome
+}
+
+// Update refreshes the configured rule sources and re-applies them to every registered project
+func (ai *AgentInitializer) Update(ctx context.Context) ([]ProjectUpdateResult, error) {
+	sources := ai.config.GetSources()
+
+	if utils.IsDebug() {
+		utils.Debugf("Update configuration details | agentPath=%s | sources=%d | projects=%d",
+			ai.agentPath, len(sources), ai.registry.GetProjectCount())
+	}
+
+	resolved, cleanup, err := ai.prepareSources(ctx, sources, true, nil)
+	if err != nil {
+		return nil, wrapOpError("Update", "sources", err, "failed to refresh agent definitions")
+	}
+	defer cleanup()
+
+	// Make sure there is something to distribute before touching any project
+	files, err := overlaySources(resolved)
+	if err != nil {
+		return nil, wrapOpError("Update", "sources", err, "failed to collect agent definitions")
+	}
+
+	release, err := ai.renderExtendedAgents(files)
+	if err != nil {
+		return nil, wrapOpError("Update", "sources", err, "failed to resolve agent inheritance")
+	}
+	defer release()
+
+	projects := ai.registry.GetProjects()
+	results := make([]ProjectUpdateResult, 0, len(projects))
+	for _, project := range projects {
+		select {
+		case <-ctx.Done():
+			return results, wrapOpError("Update", project, ctx.Err(), "update canceled")
+		default:
+		}
+
+		result := ai.updateProject(project, resolved, files)
+		utils.Infof("Project update finished | project=%s status=%s changed=%d",
+			project, result.Status, result.Changed)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// updateProject re-applies the overlaid agent definitions to a single project
+func (ai *AgentInitializer) updateProject(projectDir string, sources []*resolvedSource, files map[string]string) ProjectUpdateResult {
+	result := ProjectUpdateResult{Project: projectDir}
+
+	if !utils.DirExists(projectDir) {
+		result.Status = UpdateStatusSkipped
+		result.Detail = "project directory no longer exists"
+		return result
+	}
+
+	targetPath := filepath.Join(projectDir, ai.config.RulesDirName)
+	if !utils.DirExists(targetPath) {
+		result.Status = UpdateStatusSkipped
+		result.Detail = "rules directory not found, run init again"
+		return result
+	}
+
+	selection, err := ai.effectiveSelection(projectDir, nil)
+	if err != nil {
+		result.Status = UpdateStatusFailed
+		result.Detail = err.Error()
+		return result
+	}
+
+	changes, err := ai.syncRules(applySelection(files, selection), projectDir, nil)
+	if err != nil {
+		result.Status = UpdateStatusFailed
+		result.Detail = err.Error()
+		return result
+	}
+
+	// Re-pin the project to the commits it now matches
+	if err := ai.writeLockFile(projectDir, sources, changes); err != nil {
+		utils.Warn("Failed to update lockfile | project=" + projectDir + ", error=" + err.Error())
+	}
+
+	changed, conflicts := summarizeFileChanges(changes)
+	result.Changed = changed
+
+	switch {
+	case changed == 0:
+		result.Status = UpdateStatusUnchanged
+		result.Detail = "already up to date"
+	case conflicts > 0:
+		result.Status = UpdateStatusUpdated
+		result.Detail = fmt.Sprintf("%d file(s) changed, %d with conflicts", changed, conflicts)
+	default:
+		result.Status = UpdateStatusUpdated
+		result.Detail = fmt.Sprintf("%d file(s) changed", changed)
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"cursor++/internal/agent"
+)
+
+// RuleMatch tells whether a rule attaches to a file and why
+type RuleMatch struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Mode     string `json:"mode"` // Cursor rule type, see agent.AttachMode
+	Attached bool   `json:"attached"`
+	Glob     string `json:"glob,omitempty"` // First glob matching the file
+	Reason   string `json:"reason"`
+	Tokens   int    `json:"tokens"` // Estimated token count of the rule
+}
+
+// FileRules lists the rules of a project with whether they attach to one of its files
+type FileRules struct {
+	Path   string      `json:"path"`   // Slash separated and relative to the project root
+	Tokens int         `json:"tokens"` // Estimated token count of the attached rules together
+	Rules  []RuleMatch `json:"rules"`
+}
+
+// Attached returns the rules that attach to the file
+func (f *FileRules) Attached() []RuleMatch {
+	var attached []RuleMatch
+	for _, rule := range f.Rules {
+		if rule.Attached {
+			attached = append(attached, rule)
+		}
+	}
+	return attached
+}
+
+// WhichRules evaluates every rule of a registry against a file path relative to the project root,
+// the way Cursor decides which rules to attach. Attached rules come first, each group sorted by ID.
+func WhichRules(registry *agent.Registry, file string) FileRules {
+	result := FileRules{Path: path.Clean(strings.TrimPrefix(file, "./"))}
+
+	for _, definition := range registry.ListAgents() {
+		match := RuleMatch{ID: definition.ID, Name: definition.Name, Mode: definition.AttachMode(), Tokens: definition.Tokens}
+
+		switch match.Mode {
+		case agent.AttachAlways:
+			match.Attached = true
+			match.Reason = "alwaysApply is true"
+		case agent.AttachAuto:
+			for _, glob := range definition.Globs {
+				if MatchGlob(glob, result.Path) {
+					match.Attached = true
+					match.Glob = glob
+					match.Reason = fmt.Sprintf("matches glob %q", glob)
+					break
+				}
+			}
+			if !match.Attached {
+				match.Reason = "no glob matches: " + strings.Join(definition.Globs, ", ")
+			}
+		case agent.AttachAgentRequested:
+			match.Reason = "no globs; attached only when the AI picks it by its description"
+		default:
+			match.Reason = "no globs or description; attached only when mentioned as @" + definition.ID
+		}
+
+		if match.Attached {
+			result.Tokens += match.Tokens
+		}
+		result.Rules = append(result.Rules, match)
+	}
+
+	sort.Slice(result.Rules, func(i, j int) bool {
+		if result.Rules[i].Attached != result.Rules[j].Attached {
+			return result.Rules[i].Attached
+		}
+		return result.Rules[i].ID < result.Rules[j].ID
+	})
+	return result
+}
+
+// MatchGlob reports whether a rule glob matches a slash separated path relative to the project root.
+// Globs use path.Match syntax per path segment, plus {a,b} alternatives and ** for any number of
+// directories. A glob without a slash matches the file name in any directory, and a glob ending
+// in a slash matches everything below that directory.
+func MatchGlob(glob, file string) bool {
+	segments := strings.Split(file, "/")
+	for _, pattern := range normalizeGlob(glob) {
+		if matchSegments(strings.Split(pattern, "/"), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeGlob expands the {a,b} alternatives of a rule glob and spells each out relative to
+// the project root: ./ and / prefixes are dropped, a glob without a slash gets a **/ prefix and
+// a glob ending in a slash a ** suffix
+func normalizeGlob(glob string) []string {
+	var patterns []string
+	for _, alternative := range expandBraces(strings.TrimSpace(glob)) {
+		pattern := strings.TrimPrefix(strings.TrimPrefix(alternative, "./"), "/")
+		if pattern == "" {
+			continue
+		}
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// matchSegments matches path segments against glob segments, where a ** segment stands for
+// zero or more path segments
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		// ** inside a segment, as in foo**.go, cannot cross directories and acts as *
+		matched, err := path.Match(strings.ReplaceAll(pattern[0], "**", "*"), segments[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
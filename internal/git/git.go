@@ -21,7 +21,7 @@ func NewGitManager(service GitService) *GitManager {
 }
 
 // CloneOrPull clones a repository if it doesn't exist, or pulls if it does
-func (m *GitManager) CloneOrPull(ctx context.Context, url string, destDir string) error {
+func (m *GitManager) CloneOrPull(ctx context.Context, url string, destDir string, opts CloneOptions) error {
 	// Ensure destination directory exists
 	if err := os.MkdirAll(filepath.Dir(destDir), 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
@@ -30,7 +30,7 @@ func (m *GitManager) CloneOrPull(ctx context.Context, url string, destDir string
 	// Check if repository already exists
 	if _, err := os.Stat(filepath.Join(destDir, ".git")); os.IsNotExist(err) {
 		// Clone repository
-		if err := m.service.Clone(ctx, url, destDir); err != nil {
+		if err := m.service.Clone(ctx, url, destDir, opts); err != nil {
 			return fmt.Errorf("failed to clone repository: %w", err)
 		}
 	} else {
@@ -43,6 +43,33 @@ func (m *GitManager) CloneOrPull(ctx context.Context, url string, destDir string
 	return nil
 }
 
+// Refresh brings a cached clone up to date with its remote.
+// Without a ref the current branch is pulled; otherwise the ref is fetched and checked out,
+// and pulled as well when it names a branch.
+func (m *GitManager) Refresh(ctx context.Context, url string, repoPath string, ref string) error {
+	if ref == "" {
+		return m.CloneOrPull(ctx, url, repoPath, CloneOptions{})
+	}
+
+	if err := m.service.Fetch(ctx, repoPath); err != nil {
+		return fmt.Errorf("failed to fetch repository: %w", err)
+	}
+	if err := m.CheckoutRef(ctx, repoPath, ref); err != nil {
+		return err
+	}
+
+	branch, err := m.service.CurrentBranch(ctx, repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	if branch != "" {
+		if err := m.service.Pull(ctx, repoPath); err != nil {
+			return fmt.Errorf("failed to pull repository: %w", err)
+		}
+	}
+	return nil
+}
+
 // CheckoutRef checks out a specific reference in a repository
 func (m *GitManager) CheckoutRef(ctx context.Context, repoPath string, ref string) error {
 	if err := m.service.Checkout(ctx, repoPath, ref); err != nil {
@@ -50,3 +77,114 @@ func (m *GitManager) CheckoutRef(ctx context.Context, repoPath string, ref strin
 	}
 	return nil
 }
+
+// ResolveRef returns the commit SHA a reference points to
+func (m *GitManager) ResolveRef(ctx context.Context, repoPath string, ref string) (string, error) {
+	sha, err := m.service.RevParse(ctx, repoPath, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref %s: %w", ref, err)
+	}
+	return sha, nil
+}
+
+// HeadCommit returns the commit SHA currently checked out in a repository
+func (m *GitManager) HeadCommit(ctx context.Context, repoPath string) (string, error) {
+	return m.ResolveRef(ctx, repoPath, "HEAD")
+}
+
+// CurrentBranch returns the branch checked out in a repository, or an empty string for a detached HEAD
+func (m *GitManager) CurrentBranch(ctx context.Context, repoPath string) (string, error) {
+	return m.service.CurrentBranch(ctx, repoPath)
+}
+
+// CheckoutCommit checks out a commit, pulling first if the commit is not available locally.
+// Shallow clones are deepened if the commit is older than their history.
+// It returns the branch that was checked out before, so callers can restore it.
+func (m *GitManager) CheckoutCommit(ctx context.Context, repoPath string, url string, sha string, opts CloneOptions) (string, error) {
+	previous, err := m.service.CurrentBranch(ctx, repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current branch: %w", err)
+	}
+
+	if _, err := m.service.RevParse(ctx, repoPath, sha); err != nil {
+		// Commit is unknown locally, fetch the latest history first
+		if err := m.CloneOrPull(ctx, url, repoPath, opts); err != nil {
+			return previous, err
+		}
+	}
+
+	if _, err := m.service.RevParse(ctx, repoPath, sha); err != nil {
+		shallow, shallowErr := m.service.IsShallow(ctx, repoPath)
+		if shallowErr == nil && shallow {
+			full := opts
+			full.Depth = 0
+			if err := m.deepen(ctx, url, repoPath, full); err != nil {
+				return previous, err
+			}
+			// A fresh clone starts on the default branch
+			if previous == "" {
+				previous, _ = m.service.CurrentBranch(ctx, repoPath)
+			}
+		}
+	}
+
+	if err := m.CheckoutRef(ctx, repoPath, sha); err != nil {
+		return previous, err
+	}
+	return previous, nil
+}
+
+// EnsureCloneMode converts an existing clone to match opts: a full clone becomes shallow
+// when a depth is requested, and a shallow clone gets its full history when none is.
+// It reports whether the clone was converted.
+func (m *GitManager) EnsureCloneMode(ctx context.Context, url string, repoPath string, opts CloneOptions) (bool, error) {
+	shallow, err := m.service.IsShallow(ctx, repoPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect clone: %w", err)
+	}
+
+	wantShallow := opts.Depth > 0
+	switch {
+	case shallow == wantShallow:
+		return false, nil
+	case shallow:
+		return true, m.deepen(ctx, url, repoPath, opts)
+	default:
+		// History cannot be dropped from an existing clone, so clone again
+		return true, m.reclone(ctx, url, repoPath, opts)
+	}
+}
+
+// deepen fetches the full history of a shallow clone, cloning again if the backend cannot
+func (m *GitManager) deepen(ctx context.Context, url string, repoPath string, opts CloneOptions) error {
+	if err := m.service.Deepen(ctx, repoPath); err == nil {
+		return nil
+	}
+	return m.reclone(ctx, url, repoPath, opts)
+}
+
+// reclone replaces a clone with a fresh one. The new clone is made next to the old one
+// and swapped in only once it succeeded, so a failed clone leaves the old one intact.
+func (m *GitManager) reclone(ctx context.Context, url string, repoPath string, opts CloneOptions) error {
+	tempDir := repoPath + ".migrating"
+	oldDir := repoPath + ".old"
+	os.RemoveAll(tempDir)
+	os.RemoveAll(oldDir)
+
+	if err := m.service.Clone(ctx, url, tempDir, opts); err != nil {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	if err := os.Rename(repoPath, oldDir); err != nil {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("failed to move old clone aside: %w", err)
+	}
+	if err := os.Rename(tempDir, repoPath); err != nil {
+		os.Rename(oldDir, repoPath)
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("failed to replace clone: %w", err)
+	}
+
+	return os.RemoveAll(oldDir)
+}
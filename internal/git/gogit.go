@@ -0,0 +1,191 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+)
+
+// originRemote is the remote name used for cloned rule repositories
+const originRemote = "origin"
+
+// installFileTransport makes sure file:// repositories are served in-process.
+// go-git's default file transport runs git-upload-pack, which defeats the point of this backend.
+var installFileTransport sync.Once
+
+// GoGitService implements GitService in pure Go, without requiring a git binary
+type GoGitService struct{}
+
+// NewGoGitService creates a new GoGitService
+func NewGoGitService() GitService {
+	installFileTransport.Do(func() {
+		client.InstallProtocol("file", server.DefaultServer)
+	})
+	return &GoGitService{}
+}
+
+// Clone clones a git repository, optionally shallow.
+// Sparse paths are ignored: go-git reports files outside a sparse checkout as deleted,
+// which would block every later pull and checkout.
+func (s *GoGitService) Clone(ctx context.Context, url, dest string, opts CloneOptions) error {
+	_, err := gogit.PlainCloneContext(ctx, dest, false, &gogit.CloneOptions{URL: url, Depth: opts.Depth})
+	if err != nil {
+		return fmt.Errorf("go-git clone failed: %w", err)
+	}
+	return nil
+}
+
+// Pull fast-forwards the checked out branch to its upstream
+func (s *GoGitService) Pull(ctx context.Context, repoPath string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("go-git pull failed: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return fmt.Errorf("go-git pull failed: HEAD is detached")
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("go-git pull failed: %w", err)
+	}
+
+	err = worktree.PullContext(ctx, &gogit.PullOptions{
+		RemoteName:    originRemote,
+		ReferenceName: head.Name(),
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("go-git pull failed: %w", err)
+	}
+	return nil
+}
+
+// Fetch downloads branches and tags from the origin remote without touching the working tree
+func (s *GoGitService) Fetch(ctx context.Context, repoPath string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{
+		RemoteName: originRemote,
+		Tags:       gogit.AllTags,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("go-git fetch failed: %w", err)
+	}
+	return nil
+}
+
+// Deepen is not supported by go-git; callers fall back to cloning the full history again
+func (s *GoGitService) Deepen(ctx context.Context, repoPath string) error {
+	return errors.New("go-git cannot deepen a shallow clone")
+}
+
+// IsShallow reports whether a repository is a shallow clone
+func (s *GoGitService) IsShallow(ctx context.Context, repoPath string) (bool, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("go-git failed to read shallow commits: %w", err)
+	}
+	return len(shallow) > 0, nil
+}
+
+// Checkout checks out a branch, tag or commit.
+// Like git checkout, a branch that only exists on origin is created locally and tracks it.
+func (s *GoGitService) Checkout(ctx context.Context, repoPath, ref string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("go-git checkout failed: %w", err)
+	}
+
+	branch := plumbing.NewBranchReferenceName(ref)
+	if _, err := repo.Reference(branch, false); err == nil {
+		if err := worktree.Checkout(&gogit.CheckoutOptions{Branch: branch}); err != nil {
+			return fmt.Errorf("go-git checkout failed: %w", err)
+		}
+		return nil
+	}
+
+	if remote, err := repo.Reference(plumbing.NewRemoteReferenceName(originRemote, ref), true); err == nil {
+		if err := worktree.Checkout(&gogit.CheckoutOptions{Branch: branch, Hash: remote.Hash(), Create: true}); err != nil {
+			return fmt.Errorf("go-git checkout failed: %w", err)
+		}
+		err := repo.CreateBranch(&config.Branch{Name: ref, Remote: originRemote, Merge: branch})
+		if err != nil && !errors.Is(err, gogit.ErrBranchExists) {
+			return fmt.Errorf("go-git checkout failed to track %s: %w", ref, err)
+		}
+		return nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("go-git checkout failed: unknown ref %s: %w", ref, err)
+	}
+	if err := worktree.Checkout(&gogit.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("go-git checkout failed: %w", err)
+	}
+	return nil
+}
+
+// RevParse resolves a reference to its full commit SHA
+func (s *GoGitService) RevParse(ctx context.Context, repoPath, ref string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("go-git rev-parse failed: %w", err)
+	}
+
+	// Annotated tags resolve to the tag object; peel them to the commit
+	if tag, err := repo.TagObject(*hash); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return "", fmt.Errorf("go-git rev-parse failed: %w", err)
+		}
+		return commit.Hash.String(), nil
+	}
+	return hash.String(), nil
+}
+
+// CurrentBranch returns the checked out branch name, or an empty string for a detached HEAD
+func (s *GoGitService) CurrentBranch(ctx context.Context, repoPath string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("go-git rev-parse failed: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", nil
+	}
+	return head.Name().Short(), nil
+}
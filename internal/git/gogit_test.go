@@ -0,0 +1,172 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteFixture is a bare repository with a work tree that pushes to it
+type remoteFixture struct {
+	t    *testing.T
+	url  string // file:// URL of the bare repository
+	work string // Clone used to push new commits
+}
+
+// newRemoteFixture creates a bare repository in a temporary directory holding a main branch
+// with one commit, a feature branch and an annotated v1 tag
+func newRemoteFixture(t *testing.T) *remoteFixture {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	bare := filepath.Join(t.TempDir(), "rules.git")
+	f := &remoteFixture{t: t, url: "file://" + filepath.ToSlash(bare), work: t.TempDir()}
+	f.git("", "init", "--bare", "--initial-branch=main", bare)
+	f.git(f.work, "init", "--initial-branch=main")
+	f.git(f.work, "remote", "add", "origin", bare)
+
+	f.commit("agent.mdc", "# Agent v1\n")
+	f.git(f.work, "tag", "-a", "v1", "-m", "v1")
+	f.git(f.work, "push", "origin", "main", "v1")
+
+	f.git(f.work, "checkout", "-b", "feature")
+	f.commit("feature.mdc", "# Feature\n")
+	f.git(f.work, "push", "origin", "feature")
+	f.git(f.work, "checkout", "main")
+	return f
+}
+
+// git runs a git command in dir, failing the test on error
+func (f *remoteFixture) git(dir string, args ...string) string {
+	f.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	out, err := cmd.CombinedOutput()
+	require.NoError(f.t, err, "git %s\n%s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes a file in the work tree and commits it, returning the commit SHA
+func (f *remoteFixture) commit(name, content string) string {
+	f.t.Helper()
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.work, name), []byte(content), 0644))
+	f.git(f.work, "add", name)
+	f.git(f.work, "commit", "-m", "Update "+name)
+	return f.git(f.work, "rev-parse", "HEAD")
+}
+
+// clone clones the fixture with go-git into a temporary directory
+func (f *remoteFixture) clone(s GitService, opts CloneOptions) string {
+	f.t.Helper()
+	dest := filepath.Join(f.t.TempDir(), "cache")
+	require.NoError(f.t, s.Clone(context.Background(), f.url, dest, opts))
+	return dest
+}
+
+// readFile returns the content of a file in a repository, or an empty string if it is missing
+func readFile(t *testing.T, repoPath, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(repoPath, name))
+	if !os.IsNotExist(err) {
+		require.NoError(t, err)
+	}
+	return string(content)
+}
+
+func TestGoGitServiceClone(t *testing.T) {
+	f := newRemoteFixture(t)
+	s := NewGoGitService()
+	ctx := context.Background()
+
+	dest := f.clone(s, CloneOptions{})
+	assert.Equal(t, "# Agent v1\n", readFile(t, dest, "agent.mdc"))
+
+	branch, err := s.CurrentBranch(ctx, dest)
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+
+	shallow, err := s.IsShallow(ctx, dest)
+	require.NoError(t, err)
+	assert.False(t, shallow)
+
+	want := f.git(f.work, "rev-parse", "main")
+	head, err := s.RevParse(ctx, dest, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, want, head)
+
+	// The annotated tag is peeled to the commit it points to
+	tag, err := s.RevParse(ctx, dest, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, want, tag)
+
+	err = s.Clone(ctx, f.url+"-missing", filepath.Join(t.TempDir(), "cache"), CloneOptions{})
+	assert.Error(t, err, "cloning a missing repository should fail")
+}
+
+func TestGoGitServicePull(t *testing.T) {
+	f := newRemoteFixture(t)
+	s := NewGoGitService()
+	ctx := context.Background()
+
+	dest := f.clone(s, CloneOptions{})
+	require.NoError(t, s.Pull(ctx, dest), "pull without upstream changes")
+
+	want := f.commit("agent.mdc", "# Agent v2\n")
+	f.git(f.work, "push", "origin", "main")
+
+	require.NoError(t, s.Pull(ctx, dest))
+	assert.Equal(t, "# Agent v2\n", readFile(t, dest, "agent.mdc"))
+	head, err := s.RevParse(ctx, dest, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, want, head)
+
+	require.NoError(t, s.Checkout(ctx, dest, "v1"))
+	assert.Error(t, s.Pull(ctx, dest), "pulling with a detached HEAD should fail")
+}
+
+func TestGoGitServiceCheckout(t *testing.T) {
+	f := newRemoteFixture(t)
+	s := NewGoGitService()
+	ctx := context.Background()
+	dest := f.clone(s, CloneOptions{})
+
+	// A branch that only exists on origin is created locally and tracks it
+	require.NoError(t, s.Checkout(ctx, dest, "feature"))
+	branch, err := s.CurrentBranch(ctx, dest)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", branch)
+	assert.Equal(t, "# Feature\n", readFile(t, dest, "feature.mdc"))
+	assert.NoError(t, s.Pull(ctx, dest), "pull on the new tracking branch")
+
+	// An existing local branch is checked out again
+	require.NoError(t, s.Checkout(ctx, dest, "main"))
+	assert.Empty(t, readFile(t, dest, "feature.mdc"))
+
+	// Tags and commits leave HEAD detached
+	commit := f.git(f.work, "rev-parse", "main")
+	for _, ref := range []string{"v1", commit} {
+		require.NoError(t, s.Checkout(ctx, dest, ref))
+
+		branch, err := s.CurrentBranch(ctx, dest)
+		require.NoError(t, err)
+		assert.Empty(t, branch, "HEAD should be detached after checking out %s", ref)
+
+		head, err := s.RevParse(ctx, dest, "HEAD")
+		require.NoError(t, err)
+		assert.Equal(t, commit, head)
+	}
+
+	assert.Error(t, s.Checkout(ctx, dest, "no-such-ref"), "checking out an unknown ref should fail")
+}
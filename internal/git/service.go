@@ -4,13 +4,50 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
+// CloneOptions narrows what a clone downloads
+type CloneOptions struct {
+	Depth       int      // Number of most recent commits to fetch; 0 fetches the full history
+	SparsePaths []string // Directories to check out; empty checks out everything
+}
+
 // GitService defines the interface for Git operations
 type GitService interface {
-	Clone(ctx context.Context, url, dest string) error
+	Clone(ctx context.Context, url, dest string, opts CloneOptions) error
 	Pull(ctx context.Context, repoPath string) error
+	Fetch(ctx context.Context, repoPath string) error
+	Deepen(ctx context.Context, repoPath string) error
+	IsShallow(ctx context.Context, repoPath string) (bool, error)
 	Checkout(ctx context.Context, repoPath, ref string) error
+	RevParse(ctx context.Context, repoPath, ref string) (string, error)
+	CurrentBranch(ctx context.Context, repoPath string) (string, error)
+}
+
+// Backend names accepted by NewService
+const (
+	BackendCommand = "command" // Shell out to the git binary
+	BackendGoGit   = "go-git"  // Pure Go implementation, no git binary needed
+)
+
+// NewService returns the GitService implementation for a backend name.
+// An empty name picks the git command when it is installed and go-git otherwise.
+func NewService(backend string) (GitService, error) {
+	switch backend {
+	case BackendCommand:
+		return NewGitCommandService(nil), nil
+	case BackendGoGit:
+		return NewGoGitService(), nil
+	case "":
+		if _, err := exec.LookPath("git"); err != nil {
+			return NewGoGitService(), nil
+		}
+		return NewGitCommandService(nil), nil
+	default:
+		return nil, fmt.Errorf("unknown git backend %q (expected %q or %q)", backend, BackendCommand, BackendGoGit)
+	}
 }
 
 // CommandExecutor defines the interface for executing shell commands
@@ -40,12 +77,31 @@ func NewGitCommandService(executor CommandExecutor) GitService {
 	return &GitCommandService{executor: executor}
 }
 
-// Clone clones a git repository
-func (s *GitCommandService) Clone(ctx context.Context, url, dest string) error {
-	output, err := s.executor.Execute(ctx, "git", "clone", url, dest)
+// Clone clones a git repository, optionally shallow and limited to some directories
+func (s *GitCommandService) Clone(ctx context.Context, url, dest string, opts CloneOptions) error {
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		// Keep all branches so refs other than the default branch can still be checked out
+		args = append(args, "--depth", strconv.Itoa(opts.Depth), "--no-single-branch")
+	}
+	if len(opts.SparsePaths) > 0 {
+		// Only download the blobs of the directories that are checked out
+		args = append(args, "--sparse", "--filter=blob:none")
+	}
+	args = append(args, url, dest)
+
+	output, err := s.executor.Execute(ctx, "git", args...)
 	if err != nil {
 		return fmt.Errorf("git clone failed: %w\nOutput: %s", err, output)
 	}
+
+	if len(opts.SparsePaths) > 0 {
+		args := append([]string{"-C", dest, "sparse-checkout", "set", "--"}, opts.SparsePaths...)
+		output, err := s.executor.Execute(ctx, "git", args...)
+		if err != nil {
+			return fmt.Errorf("git sparse-checkout failed: %w\nOutput: %s", err, output)
+		}
+	}
 	return nil
 }
 
@@ -58,6 +114,33 @@ func (s *GitCommandService) Pull(ctx context.Context, repoPath string) error {
 	return nil
 }
 
+// Fetch downloads branches and tags from the origin remote without touching the working tree
+func (s *GitCommandService) Fetch(ctx context.Context, repoPath string) error {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "fetch", "--tags", "origin")
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Deepen fetches the full history of a shallow clone
+func (s *GitCommandService) Deepen(ctx context.Context, repoPath string) error {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "fetch", "--unshallow", "--tags", "origin")
+	if err != nil {
+		return fmt.Errorf("git fetch --unshallow failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// IsShallow reports whether a repository is a shallow clone
+func (s *GitCommandService) IsShallow(ctx context.Context, repoPath string) (bool, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse failed: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)) == "true", nil
+}
+
 // Checkout checks out a specific reference in a git repository
 func (s *GitCommandService) Checkout(ctx context.Context, repoPath, ref string) error {
 	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "checkout", ref)
@@ -66,3 +149,25 @@ func (s *GitCommandService) Checkout(ctx context.Context, repoPath, ref string)
 	}
 	return nil
 }
+
+// RevParse resolves a reference to its full commit SHA
+func (s *GitCommandService) RevParse(ctx context.Context, repoPath, ref string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CurrentBranch returns the checked out branch name, or an empty string for a detached HEAD
+func (s *GitCommandService) CurrentBranch(ctx context.Context, repoPath string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w\nOutput: %s", err, output)
+	}
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
@@ -18,6 +18,7 @@ type AgentDisplayOptions struct {
 	GroupByCategory bool
 	CompactMode     bool
 	SelectedAgentID string
+	ShowTokens      bool // Show the estimated token count of each agent
 }
 
 // DefaultAgentDisplayOptions returns default display options
@@ -55,8 +56,26 @@ func getTerminalWidth() int {
 	return 80
 }
 
-// detectAgentCategory attempts to determine the category of an agent
-func detectAgentCategory(agent *agent.AgentDefinition) string {
+// AgentCategories lists the categories DetectAgentCategory guesses, for offering them as choices
+var AgentCategories = []string{
+	"Architecture & Planning",
+	"Code Review",
+	"Debugging & Fixing",
+	"Documentation",
+	"General",
+	"Git & Version Control",
+	"Quick Help",
+	"Refactoring",
+	"Testing",
+	"Web Tools",
+}
+
+// DetectAgentCategory returns the category an agent declares, or guesses one from its ID and description
+func DetectAgentCategory(agent *agent.AgentDefinition) string {
+	if agent.Category != "" {
+		return agent.Category
+	}
+
 	id := strings.ToLower(agent.ID)
 
 	// Check ID for category hints
@@ -112,7 +131,7 @@ func DisplayAgentListEnhanced(agents []*agent.AgentDefinition, options AgentDisp
 		// Group agents by category
 		categories := make(map[string][]*agent.AgentDefinition)
 		for _, a := range agents {
-			category := detectAgentCategory(a)
+			category := DetectAgentCategory(a)
 			categories[category] = append(categories[category], a)
 		}
 
@@ -181,14 +200,15 @@ func displayCompactAgentGroup(agents []*agent.AgentDefinition, options AgentDisp
 		// Use ID as selector index
 		idStr := fmt.Sprintf("%s", a.ID)
 
-		// Format name with optional version
-		nameStr := a.Name
-		if a.Version != "" && a.Version != "1.0" {
-			nameStr = fmt.Sprintf("%s (%s)", a.Name, a.Version)
-		}
+		// Format name with optional icon and version
+		nameStr := formatAgentName(a)
 
 		// Print in compact format
-		fmt.Printf("%s%-20s %s\n", prefix, idStr, nameStr)
+		if options.ShowTokens {
+			fmt.Printf("%s%-20s %s [%s] %s\n", prefix, idStr, nameStr, attachSummary(a), FormatTokens(a.Tokens))
+		} else {
+			fmt.Printf("%s%-20s %s [%s]\n", prefix, idStr, nameStr, attachSummary(a))
+		}
 
 		// Add separator except after last item
 		if i < len(agents)-1 {
@@ -208,11 +228,8 @@ func displayDetailedAgentGroup(agents []*agent.AgentDefinition, options AgentDis
 		// Use ID as selector
 		idStr := fmt.Sprintf("%s", a.ID)
 
-		// Format name with optional version
-		nameStr := a.Name
-		if a.Version != "" && a.Version != "1.0" {
-			nameStr = fmt.Sprintf("%s (%s)", a.Name, a.Version)
-		}
+		// Format name with optional icon and version
+		nameStr := formatAgentName(a)
 
 		// Print detailed format
 		fmt.Printf("%s%-20s %s\n", prefix, idStr, nameStr)
@@ -223,7 +240,16 @@ func displayDetailedAgentGroup(agents []*agent.AgentDefinition, options AgentDis
 			fmt.Printf("    %s\n", shortDesc)
 		}
 
+		// Print when the rule is attached
+		fmt.Printf("    Applies: %s\n", attachSummary(a))
+		if options.ShowTokens {
+			fmt.Printf("    Size: %s\n", FormatTokens(a.Tokens))
+		}
+
 		// Print tags if available
+		if len(a.Tags) > 0 {
+			fmt.Printf("    Tags: %s\n", strings.Join(a.Tags, ", "))
+		}
 		if len(a.Templates) > 0 {
 			fmt.Printf("    Templates: %s\n", strings.Join(a.Templates, ", "))
 		}
@@ -235,6 +261,40 @@ func displayDetailedAgentGroup(agents []*agent.AgentDefinition, options AgentDis
 	}
 }
 
+// formatAgentName returns the agent name, prefixed with its icon and followed by a non-default version
+func formatAgentName(a *agent.AgentDefinition) string {
+	name := a.Name
+	if a.Icon != "" && !strings.HasPrefix(name, a.Icon) {
+		name = a.Icon + " " + name
+	}
+	if a.Version != "" && a.Version != "1.0" {
+		name = fmt.Sprintf("%s (%s)", name, a.Version)
+	}
+	return name
+}
+
+// attachSummary describes when Cursor includes an agent, with its globs if it auto-attaches
+func attachSummary(a *agent.AgentDefinition) string {
+	mode := a.AttachMode()
+	if mode == agent.AttachAuto {
+		return mode + ": " + strings.Join(a.Globs, ", ")
+	}
+	return mode
+}
+
+// FormatTokens formats an estimated token count, such as "~1,250 tokens"
+func FormatTokens(tokens int) string {
+	digits := strconv.Itoa(tokens)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return "~" + b.String() + " tokens"
+}
+
 // truncateText shortens text to fit within maxWidth characters
 func truncateText(text string, maxWidth int) string {
 	if len(text) <= maxWidth {
@@ -259,6 +319,10 @@ func DisplayAgentInfoEnhanced(agent *agent.AgentDefinition, verbose bool) error
 	Plain("  ID:        %s", agent.ID)
 	Plain("  Name:      %s", agent.Name)
 
+	if agent.Icon != "" {
+		Plain("  Icon:      %s", agent.Icon)
+	}
+
 	if agent.Version != "" {
 		Plain("  Version:   %s", agent.Version)
 	}
@@ -267,7 +331,27 @@ func DisplayAgentInfoEnhanced(agent *agent.AgentDefinition, verbose bool) error
 		Plain("  Type:      %s", agent.Type)
 	}
 
+	if agent.Author != "" {
+		Plain("  Author:    %s", agent.Author)
+	}
+
+	Plain("  Category:  %s", DetectAgentCategory(agent))
+
+	if agent.Extends != "" {
+		Plain("  Extends:   %s", agent.Extends)
+	}
+
+	if len(agent.Tags) > 0 {
+		Plain("  Tags:      %s", strings.Join(agent.Tags, ", "))
+	}
+
 	Plain("  Updated:   %s", agent.LastUpdated.Format("2006-01-02 15:04:05"))
+	Plain("  Applies:   %s", agent.AttachMode())
+	if len(agent.Globs) > 0 {
+		Plain("  Globs:     %s", strings.Join(agent.Globs, ", "))
+	}
+	Plain("  Always:    %t", agent.AlwaysApply)
+	Plain("  Size:      %s", FormatTokens(agent.Tokens))
 	fmt.Println()
 
 	// Display description
@@ -14,6 +14,7 @@ var (
 	InfoStyle    = color.New(color.FgCyan)
 	HeaderStyle  = color.New(color.FgBlue, color.Bold)
 	PromptStyle  = color.New(color.FgMagenta, color.Bold)
+	MatchStyle   = color.New(color.FgYellow, color.Bold)
 
 	// Internal references for consistency
 	successStyle = SuccessStyle
@@ -47,6 +47,205 @@ func DisplayFileTable(files []utils.FileInfo) {
 	Plain("Total: %d items", len(files))
 }
 
+// ProjectSummary is a single row of the per-project summary table
+type ProjectSummary struct {
+	Project string
+	Status  string
+	Details string
+}
+
+// DisplayProjectSummary displays the outcome of a multi-project operation in a formatted table
+func DisplayProjectSummary(rows []ProjectSummary) {
+	if len(rows) == 0 {
+		Plain("No projects found.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Project", "Status", "Details"})
+
+	counts := make(map[string]int)
+	for i, row := range rows {
+		counts[row.Status]++
+		t.AppendRow(table.Row{
+			i + 1,
+			row.Project,
+			statusColors(row.Status).Sprint(row.Status),
+			row.Details,
+		})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue}
+	t.Render()
+
+	Plain("")
+	Plain("Total: %d projects (%d updated, %d unchanged, %d skipped, %d failed)",
+		len(rows), counts["updated"], counts["unchanged"], counts["skipped"], counts["failed"])
+}
+
+// FilePlanRow is a single row of a file-level install plan
+type FilePlanRow struct {
+	Path    string
+	Action  string
+	Details string
+}
+
+// DisplayFilePlan displays the planned action for each file in a formatted table
+func DisplayFilePlan(rows []FilePlanRow) {
+	if len(rows) == 0 {
+		Plain("No files to install.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"File", "Action", "Details"})
+
+	counts := make(map[string]int)
+	for _, row := range rows {
+		counts[row.Action]++
+		t.AppendRow(table.Row{
+			row.Path,
+			actionColors(row.Action).Sprint(row.Action),
+			row.Details,
+		})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue}
+	t.Render()
+
+	Plain("")
+	Plain("Total: %d files (%d create, %d overwrite, %d unchanged, %d skip, %d other)",
+		len(rows), counts["create"], counts["overwrite"], counts["unchanged"], counts["skip"],
+		len(rows)-counts["create"]-counts["overwrite"]-counts["unchanged"]-counts["skip"])
+}
+
+// actionColors returns the table colors used for a planned file action
+func actionColors(action string) text.Colors {
+	switch action {
+	case "create":
+		return text.Colors{text.FgGreen}
+	case "overwrite", "merge":
+		return text.Colors{text.FgCyan}
+	case "conflict", "backup":
+		return text.Colors{text.FgRed, text.Bold}
+	case "skip", "keep-local":
+		return text.Colors{text.FgYellow}
+	default:
+		return text.Colors{}
+	}
+}
+
+// DisplaySourceTable displays the configured rule sources in a formatted table
+func DisplaySourceTable(sources []utils.RuleSource) {
+	if len(sources) == 0 {
+		Plain("No rule sources configured.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Priority", "Name", "Location", "Ref", "Subfolder"})
+
+	for _, source := range sources {
+		ref := source.Ref
+		if ref == "" && source.IsGit() {
+			ref = "(default branch)"
+		}
+		t.AppendRow(table.Row{
+			source.Priority,
+			source.Name,
+			source.Location(),
+			ref,
+			source.Subfolder,
+		})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue}
+	t.Render()
+}
+
+// statusColors returns the table colors used for a project status
+func statusColors(status string) text.Colors {
+	switch status {
+	case "updated":
+		return text.Colors{text.FgGreen}
+	case "skipped":
+		return text.Colors{text.FgYellow}
+	case "failed":
+		return text.Colors{text.FgRed, text.Bold}
+	default:
+		return text.Colors{}
+	}
+}
+
+// RuleCoverageRow is a single row of the rule coverage table
+type RuleCoverageRow struct {
+	ID    string
+	Globs string
+	Files int
+}
+
+// DisplayRuleCoverage displays how many files each rule's globs match, flagging rules that match none
+func DisplayRuleCoverage(rows []RuleCoverageRow) {
+	if len(rows) == 0 {
+		Plain("No rules with globs installed.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Rule", "Globs", "Files"})
+
+	for _, row := range rows {
+		files := strconv.Itoa(row.Files)
+		if row.Files == 0 {
+			files = text.Colors{text.FgYellow}.Sprint("none")
+		}
+		t.AppendRow(table.Row{row.ID, row.Globs, files})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue}
+	t.Render()
+}
+
+// DirectoryCoverageRow is a single row of the per-directory coverage table
+type DirectoryCoverageRow struct {
+	Path      string
+	Files     int
+	Uncovered int
+	Rules     int
+}
+
+// DisplayDirectoryCoverage displays the files of each directory and how many rules attach to them
+func DisplayDirectoryCoverage(rows []DirectoryCoverageRow) {
+	if len(rows) == 0 {
+		Plain("No files found.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Directory", "Files", "Without rules", "Rules"})
+
+	for _, row := range rows {
+		uncovered := strconv.Itoa(row.Uncovered)
+		if row.Uncovered > 0 {
+			uncovered = text.Colors{text.FgYellow}.Sprint(uncovered)
+		}
+		t.AppendRow(table.Row{row.Path, row.Files, uncovered, row.Rules})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue}
+	t.Render()
+}
+
 // PromptYesNo asks the user a yes/no question and returns the answer
 func PromptYesNo(question string) bool {
 	for {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -37,7 +38,7 @@ func (s *AgentSelector) Run() (*agent.AgentDefinition, error) {
 	// Group agents by category for better organization
 	categories := make(map[string][]*agent.AgentDefinition)
 	for _, a := range s.agents {
-		category := detectAgentCategory(a)
+		category := DetectAgentCategory(a)
 		categories[category] = append(categories[category], a)
 	}
 
@@ -45,8 +46,20 @@ func (s *AgentSelector) Run() (*agent.AgentDefinition, error) {
 	indexToAgent := make(map[int]*agent.AgentDefinition)
 	index := 1
 
+	// Sort categories so the numbering is stable between runs
+	categoryNames := make([]string, 0, len(categories))
+	for category := range categories {
+		categoryNames = append(categoryNames, category)
+	}
+	sort.Strings(categoryNames)
+
 	// Display agents by category
-	for category, agents := range categories {
+	for _, category := range categoryNames {
+		agents := categories[category]
+		sort.Slice(agents, func(i, j int) bool {
+			return agents[i].Name < agents[j].Name
+		})
+
 		// Skip empty categories
 		if len(agents) == 0 {
 			continue
@@ -61,11 +74,8 @@ func (s *AgentSelector) Run() (*agent.AgentDefinition, error) {
 			// Store the agent at this index
 			indexToAgent[index] = agent
 
-			// Format name with optional version
-			nameStr := agent.Name
-			if agent.Version != "" && agent.Version != "1.0" {
-				nameStr = fmt.Sprintf("%s (%s)", agent.Name, agent.Version)
-			}
+			// Format name with optional icon and version
+			nameStr := formatAgentName(agent)
 
 			// Print agent with index
 			fmt.Printf(" %2d. %-20s %s\n", index, agent.ID, nameStr)
@@ -77,6 +87,10 @@ func (s *AgentSelector) Run() (*agent.AgentDefinition, error) {
 				fmt.Printf("     %s\n", shortDesc)
 			}
 
+			if len(agent.Tags) > 0 {
+				fmt.Printf("     Tags: %s\n", strings.Join(agent.Tags, ", "))
+			}
+
 			index++
 		}
 	}
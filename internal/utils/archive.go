@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxArchiveSize is the total number of bytes ExtractArchive writes before giving up
+	MaxArchiveSize = 256 << 20
+
+	// MaxArchiveEntries is the number of entries ExtractArchive accepts in one archive
+	MaxArchiveEntries = 10000
+)
+
+// IsArchive reports whether a path names an archive format ExtractArchive understands
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip")
+}
+
+// ExtractArchive unpacks a .tar.gz, .tgz or .zip archive into destDir.
+// Only regular files and directories are extracted; entries escaping destDir are rejected.
+// Extraction fails once the archive exceeds MaxArchiveSize bytes or MaxArchiveEntries entries.
+func ExtractArchive(archivePath, destDir string, dirPerm, filePerm os.FileMode) error {
+	Debug("Extracting archive | archive=" + archivePath + ", destination=" + destDir)
+
+	if err := os.MkdirAll(destDir, dirPerm); err != nil {
+		return wrapOpError("ExtractArchive", destDir, err, "failed to create destination directory")
+	}
+
+	budget := &archiveBudget{bytes: MaxArchiveSize, entries: MaxArchiveEntries}
+	var err error
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		err = extractZip(archivePath, destDir, dirPerm, filePerm, budget)
+	} else {
+		err = extractTarGz(archivePath, destDir, dirPerm, filePerm, budget)
+	}
+	if err != nil {
+		return wrapOpError("ExtractArchive", archivePath, err, "failed to extract archive")
+	}
+
+	Debug("Archive extracted successfully | archive=" + archivePath)
+	return nil
+}
+
+// ArchiveRoot returns the directory holding the contents of an extracted archive.
+// Archives that wrap everything in a single top-level directory, like repository
+// snapshots, are unwrapped.
+func ArchiveRoot(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+func extractTarGz(archivePath, destDir string, dirPerm, filePerm os.FileMode, budget *archiveBudget) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := budget.addEntry(); err != nil {
+			return err
+		}
+		target, err := archiveTarget(destDir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, dirPerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, tr, dirPerm, filePerm, budget); err != nil {
+				return err
+			}
+		default:
+			Debug("Skipping unsupported archive entry | name=" + header.Name)
+		}
+	}
+}
+
+func extractZip(archivePath, destDir string, dirPerm, filePerm os.FileMode, budget *archiveBudget) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, entry := range zr.File {
+		if err := budget.addEntry(); err != nil {
+			return err
+		}
+		target, err := archiveTarget(destDir, entry.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := entry.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, dirPerm); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := entry.Open()
+			if err != nil {
+				return err
+			}
+			err = writeArchiveFile(target, rc, dirPerm, filePerm, budget)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			Debug("Skipping unsupported archive entry | name=" + entry.Name)
+		}
+	}
+	return nil
+}
+
+// archiveTarget resolves an archive entry name inside destDir, rejecting path traversal
+func archiveTarget(destDir, name string) (string, error) {
+	target := filepath.Join(destDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(destDir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q escapes destination directory", name)
+	}
+	return target, nil
+}
+
+// archiveBudget tracks how many bytes and entries an extraction may still write
+type archiveBudget struct {
+	bytes   int64
+	entries int
+}
+
+func (b *archiveBudget) addEntry() error {
+	if b.entries == 0 {
+		return fmt.Errorf("archive has more than %d entries", MaxArchiveEntries)
+	}
+	b.entries--
+	return nil
+}
+
+// copy copies r to w, failing once the remaining byte budget is exceeded.
+// Header sizes are not trusted; only the bytes actually read count.
+func (b *archiveBudget) copy(w io.Writer, r io.Reader) error {
+	n, err := io.Copy(w, io.LimitReader(r, b.bytes+1))
+	if err != nil {
+		return err
+	}
+	if n > b.bytes {
+		return fmt.Errorf("archive expands to more than %d bytes", int64(MaxArchiveSize))
+	}
+	b.bytes -= n
+	return nil
+}
+
+func writeArchiveFile(target string, r io.Reader, dirPerm, filePerm os.FileMode, budget *archiveBudget) error {
+	if err := os.MkdirAll(filepath.Dir(target), dirPerm); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePerm)
+	if err != nil {
+		return err
+	}
+	if err := budget.copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
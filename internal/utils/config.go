@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -26,6 +27,18 @@ const (
 	// DefaultSourceFolder is the name of the folder to copy from the cloned repo
 	DefaultSourceFolder = "default"
 
+	// DefaultRepoURL is the repository agent definitions are cloned from
+	DefaultRepoURL = "https://github.com/nsnarender5511/AgenticSystem"
+
+	// DefaultSourceName is the name of the built-in rule source
+	DefaultSourceName = "default"
+
+	// DefaultCloneDepth is the number of commits fetched when cloning a rule source
+	DefaultCloneDepth = 1
+
+	// DefaultTokenBudget is the estimated token count the rules attached to a file may add up to
+	DefaultTokenBudget = 8000
+
 	// DefaultDirPermission is the default permission for directories
 	DefaultDirPermission = 0755
 
@@ -35,14 +48,67 @@ const (
 
 // Config represents the application configuration
 type Config struct {
-	RulesDirName      string      `json:"rulesDirName"`
-	RegistryFileName  string      `json:"registryFileName"`
-	DirPermission     os.FileMode `json:"dirPermission"`
-	FilePermission    os.FileMode `json:"filePermission"`
-	MultiAgentEnabled bool        `json:"multiAgentEnabled"`
-	AgentsDirName     string      `json:"agentsDirName"`
-	LastSelectedAgent string      `json:"lastSelectedAgent"`
-	SourceFolder      string      `json:"sourceFolder"`
+	RulesDirName      string       `json:"rulesDirName"`
+	RegistryFileName  string       `json:"registryFileName"`
+	DirPermission     os.FileMode  `json:"dirPermission"`
+	FilePermission    os.FileMode  `json:"filePermission"`
+	MultiAgentEnabled bool         `json:"multiAgentEnabled"`
+	AgentsDirName     string       `json:"agentsDirName"`
+	LastSelectedAgent string       `json:"lastSelectedAgent"`
+	SourceFolder      string       `json:"sourceFolder"`
+	Sources           []RuleSource `json:"sources,omitempty"`
+	GitBackend        string       `json:"gitBackend,omitempty"` // "command", "go-git", or empty to pick automatically
+	CloneDepth        int          `json:"cloneDepth"`           // Commits to fetch when cloning; 0 fetches the full history
+	SparseCheckout    bool         `json:"sparseCheckout"`       // Only check out the subfolder of a rule source
+	TokenBudget       int          `json:"tokenBudget"`          // Estimated tokens the rules attached to a file may use; 0 disables the warning
+}
+
+// RuleSource describes a location agent definitions are installed from
+type RuleSource struct {
+	Name      string `json:"name"`                // Unique name of the source
+	URL       string `json:"url,omitempty"`       // Git repository URL
+	Path      string `json:"path,omitempty"`      // Local directory, used instead of URL
+	Ref       string `json:"ref,omitempty"`       // Branch, tag or commit to check out
+	Subfolder string `json:"subfolder,omitempty"` // Folder inside the source holding the rules
+	Priority  int    `json:"priority"`            // Higher priorities win on agent ID clashes
+}
+
+// Location returns the URL or path the source is read from
+func (s RuleSource) Location() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.Path
+}
+
+// IsGit reports whether the source is a git repository that needs to be cloned
+func (s RuleSource) IsGit() bool {
+	return s.URL != ""
+}
+
+// DefaultRuleSource returns the built-in source used when no sources are configured
+func (c *Config) DefaultRuleSource() RuleSource {
+	return RuleSource{
+		Name:      DefaultSourceName,
+		URL:       DefaultRepoURL,
+		Subfolder: c.SourceFolder,
+		Priority:  0,
+	}
+}
+
+// GetSources returns the configured rule sources ordered from lowest to highest priority.
+// When none are configured the built-in default source is returned.
+func (c *Config) GetSources() []RuleSource {
+	if len(c.Sources) == 0 {
+		return []RuleSource{c.DefaultRuleSource()}
+	}
+
+	sources := make([]RuleSource, len(c.Sources))
+	copy(sources, c.Sources)
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority < sources[j].Priority
+	})
+	return sources
 }
 
 // ConfigValidator defines a validation function for config values
@@ -72,6 +138,9 @@ func NewConfigManager() *ConfigManager {
 			MultiAgentEnabled: false,
 			LastSelectedAgent: "",
 			SourceFolder:      DefaultSourceFolder,
+			CloneDepth:        DefaultCloneDepth,
+			SparseCheckout:    true,
+			TokenBudget:       DefaultTokenBudget,
 		},
 		validators: make(map[string]ConfigValidator),
 	}
@@ -147,6 +216,11 @@ func (cm *ConfigManager) GetConfig() *Config {
 		AgentsDirName:     cm.config.AgentsDirName,
 		LastSelectedAgent: cm.config.LastSelectedAgent,
 		SourceFolder:      cm.config.SourceFolder,
+		Sources:           append([]RuleSource(nil), cm.config.Sources...),
+		GitBackend:        cm.config.GitBackend,
+		CloneDepth:        cm.config.CloneDepth,
+		SparseCheckout:    cm.config.SparseCheckout,
+		TokenBudget:       cm.config.TokenBudget,
 	}
 }
 
@@ -1,12 +1,24 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
+// skippedDirs lists directories that never contain agent definitions
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".cursor":      true,
+	".github":      true,
+	".vscode":      true,
+	"node_modules": true,
+}
+
 // DirExists checks if directory exists
 func DirExists(path string) bool {
 	_, err := os.Stat(path)
@@ -44,15 +56,6 @@ func CopyDir(src, dst string) error {
 	}
 	config := cm.GetConfig()
 
-	// Define directories to skip
-	dirsToSkip := map[string]bool{
-		".git":         true,
-		".cursor":      true,
-		".github":      true,
-		".vscode":      true,
-		"node_modules": true,
-	}
-
 	// Create destination directory
 	if err := os.MkdirAll(dst, config.DirPermission); err != nil {
 		Error("Failed to create destination directory | path=" + dst + ", error=" + err.Error())
@@ -78,7 +81,7 @@ func CopyDir(src, dst string) error {
 
 		if fileInfo.IsDir() {
 			// Skip directories that should be excluded
-			if dirsToSkip[entry.Name()] {
+			if skippedDirs[entry.Name()] {
 				Debug("Skipping excluded directory | path=" + sourcePath)
 				continue
 			}
@@ -141,6 +144,86 @@ func CopyFile(src, dst string) error {
 	return nil
 }
 
+// CollectMDCFiles returns every .mdc file below dir keyed by its path relative to dir.
+// Directories that CopyDir would skip are skipped here too.
+func CollectMDCFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := walkSourceFiles(dir, func(relPath, path string) {
+		if filepath.Ext(relPath) == ".mdc" {
+			files[relPath] = path
+		}
+	})
+	if err != nil {
+		Error("Failed to collect .mdc files | dir=" + dir + ", error=" + err.Error())
+		return nil, err
+	}
+
+	Debug(fmt.Sprintf("Collected .mdc files | dir=%s, count=%d", dir, len(files)))
+	return files, nil
+}
+
+// CollectNonMDCFiles returns the paths relative to dir of every file that is not an
+// agent definition and is therefore never installed
+func CollectNonMDCFiles(dir string) ([]string, error) {
+	var files []string
+	err := walkSourceFiles(dir, func(relPath, path string) {
+		if filepath.Ext(relPath) != ".mdc" {
+			files = append(files, relPath)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// walkSourceFiles calls fn for every regular file below dir, skipping skippedDirs
+func walkSourceFiles(dir string, fn func(relPath, path string)) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && skippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fn(relPath, path)
+		return nil
+	})
+}
+
+// HashFile returns the hex-encoded SHA-256 checksum of a file
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// HashBytes returns the hex-encoded SHA-256 checksum of data
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // CopyDirSelective copies a specific subfolder from a source directory to a destination
 // If sourceFolderName is empty, it behaves like CopyDir and copies everything
 func CopyDirSelective(src, dst, sourceFolderName string) error {
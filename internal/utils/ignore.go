@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreMatcher tells whether paths in a working tree are ignored by git
+type IgnoreMatcher struct {
+	matcher gitignore.Matcher
+}
+
+// NewIgnoreMatcher reads the .gitignore files of a directory and its subdirectories,
+// along with .git/info/exclude. A directory without any of them ignores nothing.
+func NewIgnoreMatcher(dir string) (*IgnoreMatcher, error) {
+	patterns, err := gitignore.ReadPatterns(osfs.New(dir), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitignore files: %w", err)
+	}
+	return &IgnoreMatcher{matcher: gitignore.NewMatcher(patterns)}, nil
+}
+
+// Ignored reports whether a slash separated path relative to the working tree is ignored.
+// The .git directory is always ignored.
+func (m *IgnoreMatcher) Ignored(path string, isDir bool) bool {
+	segments := strings.Split(path, "/")
+	if segments[0] == ".git" {
+		return true
+	}
+	return m.matcher.Match(segments, isDir)
+}